@@ -33,11 +33,12 @@ var ciCmd = &cli.Command{
 	},
 }
 
-func actionCI(c *cli.Context) error {
+func actionCI(c *cli.Context) (err error) {
 	meta, err := actionSetup(c)
 	if err != nil {
 		return err
 	}
+	defer meta.closeSession(&err)
 
 	includeRe := []*regexp.Regexp{}
 	for _, pattern := range meta.cfg.CI.Include {
@@ -55,7 +56,7 @@ func actionCI(c *cli.Context) error {
 		return nil
 	}
 
-	finder := discovery.NewGitBranchFinder(git.RunGit, includeRe, meta.cfg.CI.BaseBranch, meta.cfg.CI.MaxCommits, meta.cfg.Parser.CompileRelaxed())
+	finder := discovery.NewGitBranchFinder(git.RunGit, includeRe, meta.cfg.CI.BaseBranch, meta.cfg.CI.MaxCommits, meta.cfg.Parser.CompileRelaxed(), meta.cfg.Parser.CompileRuler(), meta.cfg.Parser.CompileDashboards())
 	entries, err := finder.Find()
 	if err != nil {
 		return err
@@ -68,78 +69,89 @@ func actionCI(c *cli.Context) error {
 		summary.Reports = append(summary.Reports, verifyOwners(entries)...)
 	}
 
+	reps, err := newCIReporters(meta.cfg)
+	if err != nil {
+		return err
+	}
+
+	foundBugOrHigher := false
+	bySeverity := map[string]interface{}{} // interface{} is needed for log.Fields()
+	for s, c := range summary.CountBySeverity() {
+		if s >= checks.Bug {
+			foundBugOrHigher = true
+		}
+		bySeverity[s.String()] = c
+	}
+	if len(bySeverity) > 0 {
+		log.Info().Fields(bySeverity).Msg("Problems found")
+	}
+
+	if err := submitReports(reps, summary); err != nil {
+		return fmt.Errorf("submitting reports: %w", err)
+	}
+
+	if foundBugOrHigher {
+		return fmt.Errorf("problems found")
+	}
+
+	return nil
+}
+
+// newCIReporters returns all reporters configured for CI runs,
+// problems are always printed to the console and can also be reported
+// to BitBucket or GitHub if there's a repository block in the config.
+func newCIReporters(cfg config.Config) ([]reporter.Reporter, error) {
 	reps := []reporter.Reporter{
 		reporter.NewConsoleReporter(os.Stderr),
 	}
 
-	if meta.cfg.Repository != nil && meta.cfg.Repository.BitBucket != nil {
+	if cfg.Repository != nil && cfg.Repository.BitBucket != nil {
 		token, ok := os.LookupEnv("BITBUCKET_AUTH_TOKEN")
 		if !ok {
-			return fmt.Errorf("BITBUCKET_AUTH_TOKEN env variable is required when reporting to BitBucket")
+			return nil, fmt.Errorf("BITBUCKET_AUTH_TOKEN env variable is required when reporting to BitBucket")
 		}
 
-		timeout, _ := time.ParseDuration(meta.cfg.Repository.BitBucket.Timeout)
+		timeout, _ := time.ParseDuration(cfg.Repository.BitBucket.Timeout)
 		br := reporter.NewBitBucketReporter(
 			version,
-			meta.cfg.Repository.BitBucket.URI,
+			cfg.Repository.BitBucket.URI,
 			timeout,
 			token,
-			meta.cfg.Repository.BitBucket.Project,
-			meta.cfg.Repository.BitBucket.Repository,
+			cfg.Repository.BitBucket.Project,
+			cfg.Repository.BitBucket.Repository,
 			git.RunGit,
 		)
 		reps = append(reps, br)
 	}
 
-	if meta.cfg.Repository != nil && meta.cfg.Repository.GitHub != nil {
+	if cfg.Repository != nil && cfg.Repository.GitHub != nil {
 		token, ok := os.LookupEnv("GITHUB_AUTH_TOKEN")
 		if !ok {
-			return fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
+			return nil, fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
 		}
 
 		prVal, ok := os.LookupEnv("GITHUB_PULL_REQUEST_NUMBER")
 		if !ok {
-			return fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
+			return nil, fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
 		}
 
 		prNum, err := strconv.Atoi(prVal)
 		if err != nil {
-			return fmt.Errorf("got not a valid number via GITHUB_PULL_REQUEST_NUMBER: %w", err)
+			return nil, fmt.Errorf("got not a valid number via GITHUB_PULL_REQUEST_NUMBER: %w", err)
 		}
 
-		timeout, _ := time.ParseDuration(meta.cfg.Repository.GitHub.Timeout)
+		timeout, _ := time.ParseDuration(cfg.Repository.GitHub.Timeout)
 		gr := reporter.NewGithubReporter(
-			meta.cfg.Repository.GitHub.BaseURI,
-			meta.cfg.Repository.GitHub.UploadURI,
+			cfg.Repository.GitHub.BaseURI,
+			cfg.Repository.GitHub.UploadURI,
 			timeout,
 			token,
-			meta.cfg.Repository.GitHub.Owner,
-			meta.cfg.Repository.GitHub.Repo,
+			cfg.Repository.GitHub.Owner,
+			cfg.Repository.GitHub.Repo,
 			prNum,
 			git.RunGit,
 		)
 		reps = append(reps, gr)
 	}
-
-	foundBugOrHigher := false
-	bySeverity := map[string]interface{}{} // interface{} is needed for log.Fields()
-	for s, c := range summary.CountBySeverity() {
-		if s >= checks.Bug {
-			foundBugOrHigher = true
-		}
-		bySeverity[s.String()] = c
-	}
-	if len(bySeverity) > 0 {
-		log.Info().Fields(bySeverity).Msg("Problems found")
-	}
-
-	if err := submitReports(reps, summary); err != nil {
-		return fmt.Errorf("submitting reports: %w", err)
-	}
-
-	if foundBugOrHigher {
-		return fmt.Errorf("problems found")
-	}
-
-	return nil
+	return reps, nil
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	formatFlag = "format"
+	ownerFlag  = "owner"
+	nameFlag   = "name"
+
+	dotFormat  = "dot"
+	jsonFormat = "json"
+)
+
+var graphCmd = &cli.Command{
+	Name:   "graph",
+	Usage:  "Print dependency graph of rules in specified files",
+	Action: actionGraph,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    formatFlag,
+			Aliases: []string{"f"},
+			Value:   dotFormat,
+			Usage:   "Output format, one of: dot, json",
+		},
+		&cli.StringFlag{
+			Name:  ownerFlag,
+			Value: "",
+			Usage: "Only include rules with owner matching this regexp, together with all rules they depend on or are used by",
+		},
+		&cli.StringFlag{
+			Name:  nameFlag,
+			Value: "",
+			Usage: "Only include rules with name matching this regexp, together with all rules they depend on or are used by",
+		},
+	},
+}
+
+func actionGraph(c *cli.Context) (err error) {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+	defer meta.closeSession(&err)
+
+	format := c.String(formatFlag)
+	if format != dotFormat && format != jsonFormat {
+		return fmt.Errorf("unsupported --%s value %q, must be one of: %s, %s", formatFlag, format, dotFormat, jsonFormat)
+	}
+
+	ownerRe, err := compileFilter(ownerFlag, c.String(ownerFlag))
+	if err != nil {
+		return err
+	}
+	nameRe, err := compileFilter(nameFlag, c.String(nameFlag))
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed(), meta.cfg.Parser.CompileRuler(), meta.cfg.Parser.CompileDashboards())
+	entries, err := finder.Find()
+	if err != nil {
+		return err
+	}
+
+	g := graph.New(entries)
+	nodes := g.Related(func(n *graph.Node) bool {
+		if ownerRe != nil && !ownerRe.MatchString(n.Owner) {
+			return false
+		}
+		if nameRe != nil && !nameRe.MatchString(n.Name()) {
+			return false
+		}
+		return true
+	})
+	export := graph.NewExport(nodes)
+
+	switch format {
+	case jsonFormat:
+		out, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, string(out))
+	default:
+		fmt.Fprint(os.Stdout, export.DOT())
+	}
+
+	return nil
+}
+
+func compileFilter(flag, value string) (*regexp.Regexp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(" + value + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s value: %w", flag, err)
+	}
+	return re, nil
+}
@@ -30,18 +30,19 @@ var lintCmd = &cli.Command{
 	},
 }
 
-func actionLint(c *cli.Context) error {
+func actionLint(c *cli.Context) (err error) {
 	meta, err := actionSetup(c)
 	if err != nil {
 		return err
 	}
+	defer meta.closeSession(&err)
 
 	paths := c.Args().Slice()
 	if len(paths) == 0 {
 		return fmt.Errorf("at least one file or directory required")
 	}
 
-	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed())
+	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed(), meta.cfg.Parser.CompileRuler(), meta.cfg.Parser.CompileDashboards())
 	entries, err := finder.Find()
 	if err != nil {
 		return err
@@ -9,6 +9,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/promapi"
 )
 
 const (
@@ -18,6 +19,8 @@ const (
 	offlineFlag  = "offline"
 	noColorFlag  = "no-color"
 	workersFlag  = "workers"
+	recordFlag   = "record"
+	replayFlag   = "replay"
 )
 
 var (
@@ -65,6 +68,14 @@ func newApp() *cli.App {
 				Value:   false,
 				Usage:   "Disable all check that send live queries to Prometheus servers",
 			},
+			&cli.PathFlag{
+				Name:  recordFlag,
+				Usage: "Record all Prometheus API responses to given file",
+			},
+			&cli.PathFlag{
+				Name:  replayFlag,
+				Usage: "Replay Prometheus API responses from a file created with --" + recordFlag,
+			},
 		},
 		Commands: []*cli.Command{
 			versionCmd,
@@ -73,6 +84,8 @@ func newApp() *cli.App {
 			watchCmd,
 			configCmd,
 			parseCmd,
+			graphCmd,
+			testCmd,
 		},
 	}
 }
@@ -80,6 +93,18 @@ func newApp() *cli.App {
 type actionMeta struct {
 	cfg     config.Config
 	workers int
+	session *promapi.Session
+}
+
+// closeSession closes the Prometheus session file if --record or --replay
+// flag was used, failing to close it is only reported if the action succeeded.
+func (meta actionMeta) closeSession(err *error) {
+	if meta.session == nil {
+		return
+	}
+	if cerr := meta.session.Close(); cerr != nil && *err == nil {
+		*err = fmt.Errorf("failed to close session file: %w", cerr)
+	}
 }
 
 func actionSetup(c *cli.Context) (meta actionMeta, err error) {
@@ -102,6 +127,22 @@ func actionSetup(c *cli.Context) (meta actionMeta, err error) {
 		meta.cfg.DisableOnlineChecks()
 	}
 
+	if c.IsSet(recordFlag) && c.IsSet(replayFlag) {
+		return meta, fmt.Errorf("--%s and --%s flags cannot be used together", recordFlag, replayFlag)
+	}
+	switch {
+	case c.IsSet(recordFlag):
+		meta.session, err = promapi.NewRecordSession(c.Path(recordFlag))
+	case c.IsSet(replayFlag):
+		meta.session, err = promapi.NewReplaySession(c.Path(replayFlag))
+	}
+	if err != nil {
+		return meta, err
+	}
+	if meta.session != nil {
+		meta.cfg.SetSession(meta.session)
+	}
+
 	return meta, nil
 }
 
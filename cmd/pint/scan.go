@@ -12,6 +12,7 @@ import (
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/config"
 	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
 	"github.com/cloudflare/pint/internal/output"
 	"github.com/cloudflare/pint/internal/reporter"
 )
@@ -43,6 +44,9 @@ func checkRules(ctx context.Context, workers int, cfg config.Config, entries []d
 		lastRunDuration.Set(time.Since(start).Seconds())
 	}()
 
+	// all checks will share a single rule dependency graph built for this run
+	ctx = graph.NewContext(ctx, entries)
+
 	jobs := make(chan scanJob, workers*5)
 	results := make(chan reporter.Report, workers*5)
 	wg := sync.WaitGroup{}
@@ -79,6 +83,14 @@ func checkRules(ctx context.Context, workers int, cfg config.Config, entries []d
 						Str("lines", output.FormatLineRangeString(entry.Rule.Lines())).
 						Msg("Found alerting rule")
 				}
+				if entry.Rule.DashboardQuery != nil {
+					rulesParsedTotal.WithLabelValues(config.DashboardQueryType).Inc()
+					log.Debug().
+						Str("path", entry.Path).
+						Str("query", entry.Rule.DashboardQuery.Path).
+						Str("lines", output.FormatLineRangeString(entry.Rule.Lines())).
+						Msg("Found dashboard query")
+				}
 
 				checkList := cfg.GetChecksForRule(ctx, entry.Path, entry.Rule)
 				for _, check := range checkList {
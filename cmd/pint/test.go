@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/reporter"
+	"github.com/cloudflare/pint/internal/ruletest"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const ruleTestReporter = "rule/test"
+
+var testCmd = &cli.Command{
+	Name:   "test",
+	Usage:  "Run rule unit tests from promtool compatible test files",
+	Action: actionTest,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    requireOwnerFlag,
+			Aliases: []string{"r"},
+			Value:   false,
+			Usage:   "Require all rules to have an owner set via comment",
+		},
+	},
+}
+
+func actionTest(c *cli.Context) (err error) {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+	defer meta.closeSession(&err)
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one test file required")
+	}
+
+	summary := reporter.Summary{}
+	ruleFiles := []string{}
+	seen := map[string]struct{}{}
+	for _, path := range paths {
+		f, err := ruletest.Load(path)
+		if err != nil {
+			return err
+		}
+		for _, rf := range f.RuleFiles {
+			if _, ok := seen[rf]; !ok {
+				seen[rf] = struct{}{}
+				ruleFiles = append(ruleFiles, rf)
+			}
+		}
+
+		log.Info().Str("path", path).Strs("rules", f.RuleFiles).Int("tests", len(f.Tests)).Msg("Running rule unit tests")
+		for _, failure := range f.Run() {
+			summary.Reports = append(summary.Reports, reporter.Report{
+				Path:          path,
+				ModifiedLines: []int{failure.Line},
+				Problem: checks.Problem{
+					Lines:    []int{failure.Line},
+					Reporter: ruleTestReporter,
+					Text:     failure.Text,
+					Severity: checks.Bug,
+				},
+			})
+		}
+	}
+
+	if c.Bool(requireOwnerFlag) && len(ruleFiles) > 0 {
+		finder := discovery.NewGlobFinder(ruleFiles, meta.cfg.Parser.CompileRelaxed(), meta.cfg.Parser.CompileRuler(), meta.cfg.Parser.CompileDashboards())
+		entries, err := finder.Find()
+		if err != nil {
+			return err
+		}
+		summary.Reports = append(summary.Reports, verifyOwners(entries)...)
+	}
+
+	reps, err := newCIReporters(meta.cfg)
+	if err != nil {
+		return err
+	}
+
+	if err = submitReports(reps, summary); err != nil {
+		return fmt.Errorf("submitting reports: %w", err)
+	}
+
+	if len(summary.Reports) > 0 {
+		log.Info().Int("failures", len(summary.Reports)).Msg("Rule unit tests failed")
+		return fmt.Errorf("rule unit tests failed")
+	}
+
+	log.Info().Int("files", len(paths)).Msg("All rule unit tests passed")
+	return nil
+}
@@ -73,11 +73,12 @@ var watchCmd = &cli.Command{
 	},
 }
 
-func actionWatch(c *cli.Context) error {
+func actionWatch(c *cli.Context) (err error) {
 	meta, err := actionSetup(c)
 	if err != nil {
 		return err
 	}
+	defer meta.closeSession(&err)
 
 	paths := c.Args().Slice()
 	if len(paths) == 0 {
@@ -231,7 +232,7 @@ func newProblemCollector(cfg config.Config, paths []string, minSeverity checks.S
 }
 
 func (c *problemCollector) scan(ctx context.Context, workers int) error {
-	finder := discovery.NewGlobFinder(c.paths, c.cfg.Parser.CompileRelaxed())
+	finder := discovery.NewGlobFinder(c.paths, c.cfg.Parser.CompileRelaxed(), c.cfg.Parser.CompileRuler(), c.cfg.Parser.CompileDashboards())
 	entries, err := finder.Find()
 	if err != nil {
 		return err
@@ -277,6 +278,10 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 			kind = "recording"
 			name = report.Rule.RecordingRule.Record.Value.Value
 		}
+		if report.Rule.DashboardQuery != nil {
+			kind = "dashboard"
+			name = report.Rule.DashboardQuery.Path
+		}
 		metric := prometheus.MustNewConstMetric(
 			c.problem,
 			prometheus.GaugeValue,
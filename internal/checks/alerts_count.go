@@ -6,6 +6,8 @@ import (
 	"sort"
 	"time"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/output"
 	"github.com/cloudflare/pint/internal/parser"
@@ -62,51 +64,84 @@ func (c AlertsCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 		return
 	}
 
-	var forDur time.Duration
+	alerts := len(firingAlerts(rule.AlertingRule, qr.Samples, c.step))
+
+	lines := []int{}
+	lines = append(lines, rule.AlertingRule.Expr.Lines()...)
 	if rule.AlertingRule.For != nil {
-		forDur, _ = time.ParseDuration(rule.AlertingRule.For.Value.Value)
+		lines = append(lines, rule.AlertingRule.For.Lines()...)
+	}
+	if rule.AlertingRule.KeepFiringFor != nil {
+		lines = append(lines, rule.AlertingRule.KeepFiringFor.Lines()...)
+	}
+	sort.Ints(lines)
+
+	delta := qr.End.Sub(qr.Start)
+	problems = append(problems, Problem{
+		Fragment: rule.AlertingRule.Expr.Value.Value,
+		Lines:    lines,
+		Reporter: c.Reporter(),
+		Text:     fmt.Sprintf("%s would trigger %d alert(s) in the last %s%s", promText(c.prom.Name(), qr.URI), alerts, output.HumanizeDuration(delta), incompleteText(qr.Missing())),
+		Severity: Information,
+	})
+	return
+}
+
+// alertWindow is the time range during which a single alert would be firing.
+type alertWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// firingAlerts replays range query results for an alerting rule and returns
+// the time range of every alert it would fire. Alerts with for are only firing
+// once results were present for that long, alerts with keep_firing_for are
+// only resolved once results are missing for that long.
+func firingAlerts(rule *parser.AlertingRule, samples []*model.SampleStream, step time.Duration) (alerts []alertWindow) {
+	var forDur time.Duration
+	if rule.For != nil {
+		forDur, _ = time.ParseDuration(rule.For.Value.Value)
+	}
+
+	// firing alerts will keep firing for this long after the query stops
+	// returning results, so short gaps in the results won't resolve them
+	var keepFiringForDur time.Duration
+	if rule.KeepFiringFor != nil {
+		if d, err := model.ParseDuration(rule.KeepFiringFor.Value.Value); err == nil {
+			keepFiringForDur = time.Duration(d)
+		}
 	}
 
-	var alerts int
-	for _, sample := range qr.Samples {
+	for _, sample := range samples {
 		var isAlerting, isNew bool
 		var firstTime, lastTime time.Time
 		for _, value := range sample.Values {
-			isNew = value.Timestamp.Time().After(lastTime.Add(c.step))
+			gap := step
+			if isAlerting {
+				gap += keepFiringForDur
+			}
+			isNew = value.Timestamp.Time().After(lastTime.Add(gap))
 			if isNew {
-				if rule.AlertingRule.For != nil {
+				if rule.For != nil {
 					isAlerting = false
 				} else {
 					isAlerting = true
-					alerts++
+					alerts = append(alerts, alertWindow{start: value.Timestamp.Time()})
 				}
 				firstTime = value.Timestamp.Time()
 			} else {
-				if !isAlerting && rule.AlertingRule.For != nil {
+				if !isAlerting && rule.For != nil {
 					if !value.Timestamp.Time().Before(firstTime.Add(forDur)) {
 						isAlerting = true
-						alerts++
+						alerts = append(alerts, alertWindow{start: value.Timestamp.Time()})
 					}
 				}
 			}
+			if isAlerting {
+				alerts[len(alerts)-1].end = value.Timestamp.Time().Add(keepFiringForDur)
+			}
 			lastTime = value.Timestamp.Time()
 		}
 	}
-
-	lines := []int{}
-	lines = append(lines, rule.AlertingRule.Expr.Lines()...)
-	if rule.AlertingRule.For != nil {
-		lines = append(lines, rule.AlertingRule.For.Lines()...)
-	}
-	sort.Ints(lines)
-
-	delta := qr.End.Sub(qr.Start)
-	problems = append(problems, Problem{
-		Fragment: rule.AlertingRule.Expr.Value.Value,
-		Lines:    lines,
-		Reporter: c.Reporter(),
-		Text:     fmt.Sprintf("%s would trigger %d alert(s) in the last %s", promText(c.prom.Name(), qr.URI), alerts, output.HumanizeDuration(delta)),
-		Severity: Information,
-	})
-	return
+	return alerts
 }
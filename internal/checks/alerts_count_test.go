@@ -163,8 +163,8 @@ func TestAlertsCheck(t *testing.T) {
 							),
 							generateSampleStream(
 								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*18),
-								time.Now().Add(time.Hour*18).Add(time.Hour*2),
+								time.Now().Add(time.Hour*16),
+								time.Now().Add(time.Hour*16).Add(time.Hour*2),
 								time.Minute,
 							),
 						},
@@ -227,8 +227,8 @@ func TestAlertsCheck(t *testing.T) {
 							),
 							generateSampleStream(
 								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*18),
-								time.Now().Add(time.Hour*18).Add(time.Hour*2),
+								time.Now().Add(time.Hour*16),
+								time.Now().Add(time.Hour*16).Add(time.Hour*2),
 								time.Minute,
 							),
 						},
@@ -236,6 +236,77 @@ func TestAlertsCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "keep_firing_for: 10m",
+			content:     "- alert: Foo Is Down\n  keep_firing_for: 10m\n  expr: up{job=\"foo\"} == 0\n",
+			checker:     newAlertsCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `up{job="foo"} == 0`,
+						Lines:    []int{2, 3},
+						Reporter: "alerts/count",
+						Text:     alertsText("prom", uri, 2, "1d"),
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `up{job="foo"} == 0`},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							// 5m gap is shorter than keep_firing_for, 30m gap is not
+							sampleStreamWithGaps(
+								map[string]string{"job": "foo"},
+								time.Now().Add(time.Hour*-3),
+								time.Minute*5,
+								time.Minute*5,
+								time.Minute*30,
+							),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "keep_firing_for doesn't apply to pending alerts",
+			content:     "- alert: Foo Is Down\n  for: 10m\n  keep_firing_for: 10m\n  expr: up{job=\"foo\"} == 0\n",
+			checker:     newAlertsCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `up{job="foo"} == 0`,
+						Lines:    []int{2, 3, 4},
+						Reporter: "alerts/count",
+						Text:     alertsText("prom", uri, 0, "1d"),
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `up{job="foo"} == 0`},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							sampleStreamWithGaps(
+								map[string]string{"job": "foo"},
+								time.Now().Add(time.Hour*-3),
+								time.Minute*5,
+								time.Minute*5,
+								time.Minute*30,
+							),
+						},
+					},
+				},
+			},
+		},
 		{
 			description: "{__name__=}",
 			content: `
@@ -325,8 +396,50 @@ func TestAlertsCheck(t *testing.T) {
 							),
 							generateSampleStream(
 								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*18),
-								time.Now().Add(time.Hour*18).Add(time.Hour*2),
+								time.Now().Add(time.Hour*16),
+								time.Now().Add(time.Hour*16).Add(time.Hour*2),
+								time.Minute,
+							),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "some queries failed",
+			content:     content,
+			checker:     newAlertsCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `up{job="foo"} == 0`,
+						Lines:    []int{2},
+						Reporter: "alerts/count",
+						Text:     alertsText("prom", uri, 1, "1d") + ", results might be incomplete because queries for 12h of that time range failed",
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `up{job="foo"} == 0`},
+						rangeStartCond{age: time.Hour * 13},
+					},
+					resp: respondWithTooManySamples(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `up{job="foo"} == 0`},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(
+								map[string]string{"job": "foo"},
+								time.Now().Add(time.Hour*-2),
+								time.Now().Add(time.Hour*-1),
 								time.Minute,
 							),
 						},
@@ -338,3 +451,14 @@ func TestAlertsCheck(t *testing.T) {
 
 	runTests(t, testCases)
 }
+
+// sampleStreamWithGaps returns a series with three blocks of samples,
+// each lasting for given duration, separated by given gaps.
+func sampleStreamWithGaps(labels map[string]string, from time.Time, duration, gap1, gap2 time.Duration) *model.SampleStream {
+	s := generateSampleStream(labels, from, from.Add(duration), time.Minute)
+	from = from.Add(duration).Add(gap1)
+	s.Values = append(s.Values, generateSampleStream(labels, from, from.Add(duration), time.Minute).Values...)
+	from = from.Add(duration).Add(gap2)
+	s.Values = append(s.Values, generateSampleStream(labels, from, from.Add(duration), time.Minute).Values...)
+	return s
+}
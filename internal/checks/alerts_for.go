@@ -29,15 +29,26 @@ func (c AlertsForChecksFor) Reporter() string {
 }
 
 func (c AlertsForChecksFor) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
-	if rule.AlertingRule == nil || rule.AlertingRule.For == nil {
+	if rule.AlertingRule == nil {
 		return
 	}
 
-	d, err := model.ParseDuration(rule.AlertingRule.For.Value.Value)
+	if rule.AlertingRule.For != nil {
+		problems = append(problems, c.checkField(rule.AlertingRule.For)...)
+	}
+	if rule.AlertingRule.KeepFiringFor != nil {
+		problems = append(problems, c.checkField(rule.AlertingRule.KeepFiringFor)...)
+	}
+
+	return
+}
+
+func (c AlertsForChecksFor) checkField(field *parser.YamlKeyValue) (problems []Problem) {
+	d, err := model.ParseDuration(field.Value.Value)
 	if err != nil {
 		problems = append(problems, Problem{
-			Fragment: rule.AlertingRule.For.Value.Value,
-			Lines:    rule.AlertingRule.For.Lines(),
+			Fragment: field.Value.Value,
+			Lines:    field.Lines(),
 			Reporter: c.Reporter(),
 			Text:     fmt.Sprintf("invalid duration: %s", err),
 			Severity: Bug,
@@ -47,11 +58,11 @@ func (c AlertsForChecksFor) Check(ctx context.Context, rule parser.Rule, entries
 
 	if d == 0 {
 		problems = append(problems, Problem{
-			Fragment: rule.AlertingRule.For.Value.Value,
-			Lines:    rule.AlertingRule.For.Lines(),
+			Fragment: field.Value.Value,
+			Lines:    field.Lines(),
 			Reporter: c.Reporter(),
 			Text: fmt.Sprintf("%q is the default value of %q, consider removing this line",
-				rule.AlertingRule.For.Value.Value, rule.AlertingRule.For.Key.Value),
+				field.Value.Value, field.Key.Value),
 			Severity: Information,
 		})
 	}
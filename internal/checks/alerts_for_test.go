@@ -72,6 +72,38 @@ func TestAlertsForCheck(t *testing.T) {
 				}
 			},
 		},
+		{
+			description: "invalid keep_firing_for value",
+			content:     "- alert: foo\n  expr: foo\n  keep_firing_for: abc\n",
+			checker:     newAlertsForCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "abc",
+						Lines:    []int{3},
+						Reporter: "alerts/for",
+						Text:     `invalid duration: not a valid duration string: "abc"`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "default keep_firing_for value",
+			content:     "- alert: foo\n  expr: foo\n  for: 5m\n  keep_firing_for: 0s\n",
+			checker:     newAlertsForCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "0s",
+						Lines:    []int{4},
+						Reporter: "alerts/for",
+						Text:     `"0s" is the default value of "keep_firing_for", consider removing this line`,
+						Severity: checks.Information,
+					},
+				}
+			},
+		},
 	}
 	runTests(t, testCases)
 }
@@ -0,0 +1,133 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/ruletest"
+)
+
+const (
+	AlertsTestedCheckName = "alerts/tested"
+)
+
+func NewAlertsTestedCheck(files []string, severity Severity) AlertsTestedCheck {
+	return AlertsTestedCheck{files: files, severity: severity}
+}
+
+type AlertsTestedCheck struct {
+	files    []string
+	severity Severity
+}
+
+func (c AlertsTestedCheck) String() string {
+	return AlertsTestedCheckName
+}
+
+func (c AlertsTestedCheck) Reporter() string {
+	return AlertsTestedCheckName
+}
+
+func (c AlertsTestedCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.AlertingRule == nil {
+		return nil
+	}
+
+	var path string
+	for _, entry := range entries {
+		if entry.Rule.AlertingRule == rule.AlertingRule {
+			path = entry.Path
+			break
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	name := rule.AlertingRule.Alert.Value.Value
+	fragment := fmt.Sprintf("%s: %s", rule.AlertingRule.Alert.Key.Value, name)
+
+	for _, pattern := range c.files {
+		found, err := testFilesCover(pattern, path, name)
+		if err != nil {
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    rule.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("failed to read rule test files from %q: %s", pattern, err),
+				Severity: Bug,
+			})
+			return problems
+		}
+		if found {
+			return nil
+		}
+	}
+
+	problems = append(problems, Problem{
+		Fragment: fragment,
+		Lines:    rule.Lines(),
+		Reporter: c.Reporter(),
+		Text:     fmt.Sprintf("alerting rule %q doesn't have any unit tests, add an alert_rule_test for it to a rule test file", name),
+		Severity: c.severity,
+	})
+	return problems
+}
+
+type cachedTestFile struct {
+	modTime time.Time
+	file    ruletest.File
+}
+
+var (
+	testFileCacheLock sync.Mutex
+	testFileCache     = map[string]cachedTestFile{}
+)
+
+// testFilesCover returns true if any rule test file matching given glob pattern
+// has tests for given alert defined in a rule file at given path.
+func testFilesCover(pattern, path, name string) (bool, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return false, err
+	}
+	for _, tp := range paths {
+		f, err := loadTestFile(tp)
+		if err != nil {
+			return false, err
+		}
+		if f.Covers(path, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadTestFile returns parsed rule test file, results are cached until the file is modified.
+func loadTestFile(path string) (ruletest.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ruletest.File{}, err
+	}
+
+	testFileCacheLock.Lock()
+	defer testFileCacheLock.Unlock()
+
+	if tf, ok := testFileCache[path]; ok && tf.modTime.Equal(info.ModTime()) {
+		return tf.file, nil
+	}
+
+	f, err := ruletest.Load(path)
+	if err != nil {
+		return f, err
+	}
+
+	testFileCache[path] = cachedTestFile{modTime: info.ModTime(), file: f}
+	return f, nil
+}
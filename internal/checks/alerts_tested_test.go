@@ -0,0 +1,112 @@
+package checks_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestAlertsTestedCheck(t *testing.T) {
+	dir := t.TempDir()
+	rules := "- alert: Tested\n  expr: up == 0\n- alert: NotTested\n  expr: up == 0\n- record: foo\n  expr: sum(up)\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yml"), []byte(rules), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "tests"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tests", "rules_test.yml"), []byte(`rule_files:
+- ../rules.yml
+tests:
+- alert_rule_test:
+  - eval_time: 1m
+    alertname: Tested
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tests", "other_test.yml"), []byte(`rule_files:
+- ../other.yml
+tests:
+- alert_rule_test:
+  - eval_time: 1m
+    alertname: NotTested
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yml"), []byte("rule_files: {}\n"), 0o644))
+
+	type testCaseT struct {
+		description string
+		files       []string
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no test files",
+			problems: []checks.Problem{
+				{
+					Fragment: "alert: Tested",
+					Lines:    []int{1, 2},
+					Reporter: checks.AlertsTestedCheckName,
+					Text:     `alerting rule "Tested" doesn't have any unit tests, add an alert_rule_test for it to a rule test file`,
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "alert: NotTested",
+					Lines:    []int{3, 4},
+					Reporter: checks.AlertsTestedCheckName,
+					Text:     `alerting rule "NotTested" doesn't have any unit tests, add an alert_rule_test for it to a rule test file`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "alerts with tests",
+			files:       []string{filepath.Join(dir, "tests", "*.yml")},
+			problems: []checks.Problem{
+				{
+					Fragment: "alert: NotTested",
+					Lines:    []int{3, 4},
+					Reporter: checks.AlertsTestedCheckName,
+					Text:     `alerting rule "NotTested" doesn't have any unit tests, add an alert_rule_test for it to a rule test file`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "broken test file",
+			files:       []string{filepath.Join(dir, "*.yml")},
+			problems: []checks.Problem{
+				{
+					Fragment: "alert: Tested",
+					Lines:    []int{1, 2},
+					Reporter: checks.AlertsTestedCheckName,
+					Text:     fmt.Sprintf("failed to read rule test files from %q: failed to parse %s: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!map into []string", filepath.Join(dir, "*.yml"), filepath.Join(dir, "broken.yml")),
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "alert: NotTested",
+					Lines:    []int{3, 4},
+					Reporter: checks.AlertsTestedCheckName,
+					Text:     fmt.Sprintf("failed to read rule test files from %q: failed to parse %s: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!map into []string", filepath.Join(dir, "*.yml"), filepath.Join(dir, "broken.yml")),
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entries := mustParseContent(rules)
+			for i := range entries {
+				entries[i].Path = filepath.Join(dir, "rules.yml")
+			}
+
+			c := checks.NewAlertsTestedCheck(tc.files, checks.Warning)
+			var problems []checks.Problem
+			for _, entry := range entries {
+				problems = append(problems, c.Check(context.Background(), entry.Rule, entries)...)
+			}
+			require.Equal(t, tc.problems, problems)
+		})
+	}
+}
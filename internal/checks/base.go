@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
 	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 )
@@ -20,18 +24,27 @@ var (
 		ComparisonCheckName,
 		FragileCheckName,
 		RateCheckName,
+		CounterCheckName,
+		HistogramCheckName,
 		RegexpCheckName,
 		SyntaxCheckName,
 		VectorMatchingCheckName,
+		LabelsConflictCheckName,
 		CostCheckName,
 		SeriesCheckName,
 		LabelCheckName,
 		RejectCheckName,
+		RuleGroupCheckName,
+		RuleDuplicateCheckName,
+		RuleDependencyCheckName,
+		NamingCheckName,
+		RuleUnusedCheckName,
+		AlertsTestedCheckName,
 	}
 	OnlineChecks = []string{
 		AlertsCheckName,
 		RateCheckName,
-		VectorMatchingCheckName,
+		CounterCheckName,
 		CostCheckName,
 		SeriesCheckName,
 	}
@@ -89,6 +102,9 @@ type Problem struct {
 	Reporter string
 	Text     string
 	Severity Severity
+	// Position is the exact location of the problem in the file,
+	// if the check was able to find it.
+	Position *parser.PositionRange
 }
 
 func (p Problem) LineRange() (int, int) {
@@ -105,6 +121,15 @@ type exprProblem struct {
 	expr     string
 	text     string
 	severity Severity
+	node     promParser.Node
+}
+
+// exprPosition returns the location of given query node in the source file.
+func exprPosition(expr parser.PromQLExpr, node promParser.Node) *parser.PositionRange {
+	if node == nil {
+		return nil
+	}
+	return expr.PositionRange(node.PositionRange())
 }
 
 func textAndSeverityFromError(err error, reporter, prom string, s Severity) (text string, severity Severity) {
@@ -137,3 +162,12 @@ func textAndSeverityFromError(err error, reporter, prom string, s Severity) (tex
 func promText(name, uri string) string {
 	return fmt.Sprintf("prometheus %q at %s", name, uri)
 }
+
+// incompleteText returns a note that should be added to problems based on
+// range query results that are missing some time ranges.
+func incompleteText(missing time.Duration) string {
+	if missing == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", results might be incomplete because queries for %s of that time range failed", output.HumanizeDuration(missing))
+}
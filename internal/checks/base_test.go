@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -60,7 +61,7 @@ func simpleProm(name, uri string, timeout time.Duration, required bool) *promapi
 	return promapi.NewFailoverGroup(
 		name,
 		[]*promapi.Prometheus{
-			promapi.NewPrometheus(name, uri, timeout),
+			promapi.NewPrometheus(name, uri, nil, timeout, nil),
 		},
 		required,
 	)
@@ -220,12 +221,35 @@ func (fc formCond) isMatch(r *http.Request) bool {
 	return r.Form.Get(fc.key) == fc.value
 }
 
+// rangeStartCond matches range queries with start time older than given age.
+type rangeStartCond struct {
+	age time.Duration
+}
+
+func (rsc rangeStartCond) isMatch(r *http.Request) bool {
+	err := r.ParseForm()
+	if err != nil {
+		return false
+	}
+	start, err := strconv.ParseFloat(r.Form.Get("start"), 64)
+	if err != nil {
+		return false
+	}
+	return time.Since(time.Unix(int64(start), 0)) > rsc.age
+}
+
 var (
 	requireConfigPath     = requestPathCond{path: "/api/v1/status/config"}
 	requireQueryPath      = requestPathCond{path: "/api/v1/query"}
 	requireRangeQueryPath = requestPathCond{path: "/api/v1/query_range"}
+	requireMetadataPath   = requestPathCond{path: "/api/v1/metadata"}
+	requireLabelsPath     = requestPathCond{path: "/api/v1/labels"}
 )
 
+func requireLabelValuesPath(name string) requestPathCond {
+	return requestPathCond{path: fmt.Sprintf("/api/v1/label/%s/values", name)}
+}
+
 type promError struct {
 	code      int
 	errorType v1.ErrorType
@@ -332,6 +356,52 @@ func (cr configResponse) respond(w http.ResponseWriter) {
 	_, _ = w.Write(d)
 }
 
+type metadataResponse struct {
+	metadata map[string][]v1.Metadata
+}
+
+func (mr metadataResponse) respond(w http.ResponseWriter) {
+	w.WriteHeader(200)
+	w.Header().Set("Content-Type", "application/json")
+	result := struct {
+		Status string                   `json:"status"`
+		Data   map[string][]v1.Metadata `json:"data"`
+	}{
+		Status: "success",
+		Data:   mr.metadata,
+	}
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = w.Write(d)
+}
+
+type labelsResponse struct {
+	values []string
+}
+
+func (lr labelsResponse) respond(w http.ResponseWriter) {
+	w.WriteHeader(200)
+	w.Header().Set("Content-Type", "application/json")
+	values := lr.values
+	if values == nil {
+		values = []string{}
+	}
+	result := struct {
+		Status string   `json:"status"`
+		Data   []string `json:"data"`
+	}{
+		Status: "success",
+		Data:   values,
+	}
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = w.Write(d)
+}
+
 type sleepResponse struct {
 	sleep time.Duration
 }
@@ -347,6 +417,9 @@ var (
 	respondWithInternalError = func() responseWriter {
 		return promError{code: 500, errorType: v1.ErrServer, err: "internal error"}
 	}
+	respondWithTooManySamples = func() responseWriter {
+		return promError{code: 422, errorType: v1.ErrExec, err: "query processing would load too many samples into memory in query execution"}
+	}
 	respondWithEmptyVector = func() responseWriter {
 		return vectorResponse{samples: model.Vector{}}
 	}
@@ -0,0 +1,157 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/utils"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	LabelsConflictCheckName = "labels/conflict"
+
+	maxConflictExamples = 5
+)
+
+func NewLabelsConflictCheck(prom *promapi.FailoverGroup) LabelsConflictCheck {
+	return LabelsConflictCheck{prom: prom}
+}
+
+type LabelsConflictCheck struct {
+	prom *promapi.FailoverGroup
+}
+
+func (c LabelsConflictCheck) String() string {
+	if c.prom == nil {
+		return LabelsConflictCheckName
+	}
+	return fmt.Sprintf("%s(%s)", LabelsConflictCheckName, c.prom.Name())
+}
+
+func (c LabelsConflictCheck) Reporter() string {
+	return LabelsConflictCheckName
+}
+
+func (c LabelsConflictCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	expr := rule.Expr()
+	if expr.SyntaxError != nil {
+		return nil
+	}
+
+	var ruleLabels *parser.YamlMap
+	if rule.AlertingRule != nil {
+		ruleLabels = rule.AlertingRule.Labels
+	}
+	if rule.RecordingRule != nil {
+		ruleLabels = rule.RecordingRule.Labels
+	}
+	if ruleLabels == nil {
+		return nil
+	}
+
+	// labels that we know will be present on results are reported without
+	// querying Prometheus, everything else needs to be checked using live data
+	ls := utils.OutputLabels(expr.Query, recordingRules(entries))
+	for _, label := range ruleLabels.Items {
+		name := label.Key.Value
+		if !ls.CanHave(name) || isLabelTemplate(label.Value.Value, name) {
+			continue
+		}
+		// every result will already have the same value, nothing is overwritten
+		if v, ok := ls.Value(name); ok && v == label.Value.Value {
+			continue
+		}
+
+		if ls.MustHave(name) || ls.Fixed {
+			if c.prom != nil {
+				continue
+			}
+			verb := "might overwrite"
+			if ls.MustHave(name) {
+				verb = "will overwrite"
+			}
+			problems = append(problems, Problem{
+				Fragment: fmt.Sprintf("%s: %s", name, label.Value.Value),
+				Lines:    label.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("%s label set to %q %s the value of %s label returned by the query", name, label.Value.Value, verb, name),
+				Severity: Warning,
+			})
+			continue
+		}
+
+		if c.prom == nil {
+			continue
+		}
+
+		values, uri, err := c.queryValues(ctx, expr, name)
+		if err != nil {
+			text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+			problems = append(problems, Problem{
+				Fragment: expr.Value.Value,
+				Lines:    expr.Lines(),
+				Reporter: c.Reporter(),
+				Text:     text,
+				Severity: severity,
+			})
+			return problems
+		}
+		if len(values) == 0 {
+			continue
+		}
+		problems = append(problems, Problem{
+			Fragment: fmt.Sprintf("%s: %s", name, label.Value.Value),
+			Lines:    label.Lines(),
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("%s label set to %q will overwrite the value of %s label returned by the query, %s is returning series with %s",
+				name, label.Value.Value, name, promText(c.prom.Name(), uri), formatLabelValues(name, values)),
+			Severity: Warning,
+		})
+	}
+
+	return problems
+}
+
+// queryValues returns all values of given label present on query results.
+func (c LabelsConflictCheck) queryValues(ctx context.Context, expr parser.PromQLExpr, name string) (values []string, uri string, err error) {
+	q := fmt.Sprintf("count(%s) by(%s)", utils.RemoveConditions(expr.Value.Value).String(), name)
+	qr, err := c.prom.Query(ctx, q)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, s := range qr.Series {
+		if v, ok := s.Metric[model.LabelName(name)]; ok && v != "" {
+			values = append(values, string(v))
+		}
+	}
+	sort.Strings(values)
+	return values, qr.URI, nil
+}
+
+// isLabelTemplate returns true if label value is using the value of the same
+// label returned by the query, which means that it's overwritten on purpose.
+func isLabelTemplate(value, name string) bool {
+	return strings.Contains(value, "$labels."+name) || strings.Contains(value, ".Labels."+name)
+}
+
+func formatLabelValues(name string, values []string) string {
+	examples := make([]string, 0, len(values))
+	for i, v := range values {
+		if i >= maxConflictExamples {
+			break
+		}
+		examples = append(examples, fmt.Sprintf("%s=%q", name, v))
+	}
+	text := strings.Join(examples, ", ")
+	if len(values) > maxConflictExamples {
+		text += fmt.Sprintf(" and %d more value(s)", len(values)-maxConflictExamples)
+	}
+	return text
+}
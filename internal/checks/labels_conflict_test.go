@@ -0,0 +1,229 @@
+package checks_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func newLabelsConflictCheck(uri string) checks.RuleChecker {
+	return checks.NewLabelsConflictCheck(simpleProm("prom", uri, time.Second, true))
+}
+
+func newLabelsConflictCheckOffline(_ string) checks.RuleChecker {
+	return checks.NewLabelsConflictCheck(nil)
+}
+
+func labelsConflictText(name, value, verb string) string {
+	return fmt.Sprintf("%s label set to %q %s the value of %s label returned by the query", name, value, verb, name)
+}
+
+func TestLabelsConflictCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- alert: foo\n  expr: sum(foo) without(\n  labels:\n    job: bar\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems:    noProblems,
+		},
+		{
+			description: "ignores rules without labels",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newLabelsConflictCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "offline / label removed by aggregation",
+			content:     "- alert: foo\n  expr: sum(up) by(job) == 0\n  labels:\n    instance: bar\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems:    noProblems,
+		},
+		{
+			description: "offline / unknown labels",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: critical\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems:    noProblems,
+		},
+		{
+			description: "offline / label kept by aggregation",
+			content:     "- alert: foo\n  expr: sum(up) by(job, severity) == 0\n  labels:\n    severity: critical\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "severity: critical",
+						Lines:    []int{4},
+						Reporter: checks.LabelsConflictCheckName,
+						Text:     labelsConflictText("severity", "critical", "might overwrite"),
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "offline / label set by selector",
+			content:     "- record: foo\n  expr: up{job=\"bar\"}\n  labels:\n    job: foo\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "job: foo",
+						Lines:    []int{4},
+						Reporter: checks.LabelsConflictCheckName,
+						Text:     labelsConflictText("job", "foo", "will overwrite"),
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "offline / label set by recording rule",
+			content:     "- alert: foo\n  expr: job:up:sum == 0\n  labels:\n    cluster: dev\n",
+			checker:     newLabelsConflictCheckOffline,
+			entries:     mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n  labels:\n    cluster: prod\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "cluster: dev",
+						Lines:    []int{4},
+						Reporter: checks.LabelsConflictCheckName,
+						Text:     labelsConflictText("cluster", "dev", "will overwrite"),
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "offline / label set by selector to the same value",
+			content:     "- alert: foo\n  expr: up{job=\"foo\"} == 0\n  labels:\n    job: foo\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems:    noProblems,
+		},
+		{
+			description: "offline / label set by recording rule to the same value",
+			content:     "- alert: foo\n  expr: job:up:sum == 0\n  labels:\n    cluster: prod\n",
+			checker:     newLabelsConflictCheckOffline,
+			entries:     mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n  labels:\n    cluster: prod\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "online / label set by selector to the same value",
+			content:     "- alert: foo\n  expr: up{job=\"foo\"} == 0\n  labels:\n    job: foo\n",
+			checker:     newLabelsConflictCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "offline / label template",
+			content:     "- alert: foo\n  expr: up{instance=\"a\"} == 0\n  labels:\n    instance: '{{ $labels.instance }}:9100'\n",
+			checker:     newLabelsConflictCheckOffline,
+			problems:    noProblems,
+		},
+		{
+			description: "online / skips labels known statically",
+			content:     "- alert: foo\n  expr: sum(up) by(job, severity) == 0\n  labels:\n    severity: critical\n",
+			checker:     newLabelsConflictCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "online / no conflicts",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: critical\n",
+			checker:     newLabelsConflictCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(up) by(severity)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{}),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "online / conflicts",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: critical\n    instance: foo\n",
+			checker:     newLabelsConflictCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "severity: critical",
+						Lines:    []int{4},
+						Reporter: checks.LabelsConflictCheckName,
+						Text:     fmt.Sprintf(`severity label set to "critical" will overwrite the value of severity label returned by the query, prometheus "prom" at %s is returning series with severity="page", severity="ticket"`, uri),
+						Severity: checks.Warning,
+					},
+					{
+						Fragment: "instance: foo",
+						Lines:    []int{5},
+						Reporter: checks.LabelsConflictCheckName,
+						Text:     fmt.Sprintf(`instance label set to "foo" will overwrite the value of instance label returned by the query, prometheus "prom" at %s is returning series with instance="a", instance="b", instance="c", instance="d", instance="e" and 2 more value(s)`, uri),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(up) by(severity)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{"severity": "ticket"}),
+							generateSample(map[string]string{}),
+							generateSample(map[string]string{"severity": "page"}),
+						},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(up) by(instance)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{"instance": "g"}),
+							generateSample(map[string]string{"instance": "f"}),
+							generateSample(map[string]string{"instance": "e"}),
+							generateSample(map[string]string{"instance": "d"}),
+							generateSample(map[string]string{"instance": "c"}),
+							generateSample(map[string]string{"instance": "b"}),
+							generateSample(map[string]string{"instance": "a"}),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "online / 500 error",
+			content:     "- record: foo\n  expr: sum(up) without(job)\n  labels:\n    instance: foo\n",
+			checker:     newLabelsConflictCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "sum(up) without(job)",
+						Lines:    []int{2},
+						Reporter: checks.LabelsConflictCheckName,
+						Text:     checkErrorUnableToRun(checks.LabelsConflictCheckName, "prom", uri, "server_error: server error: 500"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,213 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	CounterCheckName = "promql/counter"
+)
+
+func NewCounterCheck(prom *promapi.FailoverGroup) CounterCheck {
+	return CounterCheck{prom: prom}
+}
+
+type CounterCheck struct {
+	prom *promapi.FailoverGroup
+}
+
+func (c CounterCheck) String() string {
+	return fmt.Sprintf("%s(%s)", CounterCheckName, c.prom.Name())
+}
+
+func (c CounterCheck) Reporter() string {
+	return CounterCheckName
+}
+
+func (c CounterCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	expr := rule.Expr()
+
+	if expr.SyntaxError != nil {
+		return
+	}
+
+	done, err := c.checkNode(ctx, expr.Query)
+	if err != nil {
+		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+		problems = append(problems, Problem{
+			Fragment: expr.Value.Value,
+			Lines:    expr.Lines(),
+			Reporter: c.Reporter(),
+			Text:     text,
+			Severity: severity,
+		})
+		return
+	}
+
+	for _, problem := range done {
+		problems = append(problems, Problem{
+			Fragment: problem.expr,
+			Lines:    expr.Lines(),
+			Reporter: c.Reporter(),
+			Text:     problem.text,
+			Severity: problem.severity,
+			Position: exprPosition(expr, problem.node),
+		})
+	}
+
+	return
+}
+
+func (c CounterCheck) checkNode(ctx context.Context, node *parser.PromQLNode) (problems []exprProblem, err error) {
+	if n, ok := node.Node.(*promParser.Call); ok {
+		var p []exprProblem
+		switch n.Func.Name {
+		case "rate", "irate", "increase":
+			for _, vs := range callSelectors(n.Args) {
+				if p, err = c.checkType(ctx, node, n, vs, "counters", v1.MetricTypeGauge); err != nil {
+					return nil, err
+				}
+				problems = append(problems, p...)
+			}
+		case "deriv", "delta", "idelta":
+			for _, vs := range callSelectors(n.Args) {
+				if p, err = c.checkType(ctx, node, n, vs, "gauges", v1.MetricTypeCounter); err != nil {
+					return nil, err
+				}
+				problems = append(problems, p...)
+			}
+		case "histogram_quantile":
+			if len(n.Args) == 2 {
+				for _, vs := range callSelectors(n.Args[1:]) {
+					if p, err = c.checkHistogram(ctx, node, vs); err != nil {
+						return nil, err
+					}
+					problems = append(problems, p...)
+				}
+			}
+		}
+	}
+
+	for _, child := range node.Children {
+		p, err := c.checkNode(ctx, child)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, p...)
+	}
+
+	return problems, nil
+}
+
+// checkType reports a problem if metadata says that given metric
+// is of a type that shouldn't be used with called function.
+func (c CounterCheck) checkType(ctx context.Context, node *parser.PromQLNode, call *promParser.Call, vs *promParser.VectorSelector, expected string, bad v1.MetricType) (problems []exprProblem, err error) {
+	if vs.Name == "" {
+		return nil, nil
+	}
+	metadata, err := c.prom.Metadata(ctx, vs.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.Metadata) == 0 && strings.HasSuffix(vs.Name, "_total") {
+		// OpenMetrics counters are exposed with the _total suffix but their
+		// metadata is stored using the base name, without it
+		if metadata, err = c.counterMetadata(ctx, strings.TrimSuffix(vs.Name, "_total")); err != nil {
+			return nil, err
+		}
+	}
+	if !hasOnlyType(metadata.Metadata, bad) {
+		return nil, nil
+	}
+
+	problems = append(problems, exprProblem{
+		expr: node.Expr,
+		text: fmt.Sprintf("%s() should only be used with %s but %q is a %s according to metrics metadata from %s",
+			call.Func.Name, expected, vs.Name, bad, promText(c.prom.Name(), metadata.URI)),
+		severity: Bug,
+		node:     vs,
+	})
+	return problems, nil
+}
+
+// counterMetadata returns only counter metadata for given metric,
+// metrics of any other type are never exposed with the _total suffix.
+func (c CounterCheck) counterMetadata(ctx context.Context, metric string) (*promapi.MetadataResult, error) {
+	metadata, err := c.prom.Metadata(ctx, metric)
+	if err != nil {
+		return nil, err
+	}
+	counters := &promapi.MetadataResult{URI: metadata.URI}
+	for _, m := range metadata.Metadata {
+		if m.Type == v1.MetricTypeCounter {
+			counters.Metadata = append(counters.Metadata, m)
+		}
+	}
+	return counters, nil
+}
+
+// checkHistogram reports a problem if histogram_quantile() is used on a metric
+// that metadata says is not a histogram.
+func (c CounterCheck) checkHistogram(ctx context.Context, node *parser.PromQLNode, vs *promParser.VectorSelector) (problems []exprProblem, err error) {
+	if vs.Name == "" {
+		return nil, nil
+	}
+	// histogram metadata is exposed using the base name, without the _bucket suffix
+	metric := strings.TrimSuffix(vs.Name, "_bucket")
+
+	metadata, err := c.prom.Metadata(ctx, metric)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.Metadata) == 0 {
+		return nil, nil
+	}
+	for _, m := range metadata.Metadata {
+		if m.Type == v1.MetricTypeHistogram || m.Type == v1.MetricTypeGaugeHistogram || m.Type == v1.MetricTypeUnknown {
+			return nil, nil
+		}
+	}
+
+	problems = append(problems, exprProblem{
+		expr: node.Expr,
+		text: fmt.Sprintf("histogram_quantile() should only be used with histogram _bucket series but %q is a %s according to metrics metadata from %s",
+			metric, metadata.Metadata[0].Type, promText(c.prom.Name(), metadata.URI)),
+		severity: Bug,
+		node:     vs,
+	})
+	return problems, nil
+}
+
+// callSelectors returns all vector selectors used by function arguments.
+func callSelectors(args promParser.Expressions) (vs []*promParser.VectorSelector) {
+	for _, arg := range args {
+		promParser.Inspect(arg, func(n promParser.Node, _ []promParser.Node) error {
+			if s, ok := n.(*promParser.VectorSelector); ok {
+				vs = append(vs, s)
+			}
+			return nil
+		})
+	}
+	return vs
+}
+
+func hasOnlyType(metadata []v1.Metadata, typ v1.MetricType) bool {
+	if len(metadata) == 0 {
+		return false
+	}
+	for _, m := range metadata {
+		if m.Type != typ {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,351 @@
+package checks_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func newCounterCheck(uri string) checks.RuleChecker {
+	return checks.NewCounterCheck(simpleProm("prom", uri, time.Second, true))
+}
+
+func counterText(name, uri, fun, expected, metric, typ string) string {
+	return fmt.Sprintf(`%s() should only be used with %s but %q is a %s according to metrics metadata from prometheus %q at %s`, fun, expected, metric, typ, name, uri)
+}
+
+func histogramText(name, uri, metric, typ string) string {
+	return fmt.Sprintf(`histogram_quantile() should only be used with histogram _bucket series but %q is a %s according to metrics metadata from prometheus %q at %s`, metric, typ, name, uri)
+}
+
+func TestCounterCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "ignores queries without functions",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "rate() on counter",
+			content:     "- record: foo\n  expr: rate(foo[5m])\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeCounter}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate() on metric without metadata",
+			content:     "- record: foo\n  expr: rate(foo[5m])\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp:  metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+			},
+		},
+		{
+			description: "rate() on gauge",
+			content:     "- record: foo\n  expr: sum(rate(foo[5m]))\n",
+			checker:     newCounterCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "rate(foo[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     counterText("prom", uri, "rate", "counters", "foo", "gauge"),
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 18},
+							End:   parser.Position{Line: 2, Column: 20},
+						},
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeGauge}},
+					}},
+				},
+			},
+		},
+		{
+			description: "increase() on metric with mixed types",
+			content:     "- record: foo\n  expr: increase(foo[5m])\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeGauge}, {Type: v1.MetricTypeCounter}},
+					}},
+				},
+			},
+		},
+		{
+			description: "irate() and increase() on gauges",
+			content:     "- record: foo\n  expr: irate(foo[5m]) / increase(bar[5m])\n",
+			checker:     newCounterCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "irate(foo[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     counterText("prom", uri, "irate", "counters", "foo", "gauge"),
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 15},
+							End:   parser.Position{Line: 2, Column: 17},
+						},
+					},
+					{
+						Fragment: "increase(bar[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     counterText("prom", uri, "increase", "counters", "bar", "gauge"),
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 35},
+							End:   parser.Position{Line: 2, Column: 37},
+						},
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeGauge}},
+					}},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "bar"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"bar": {{Type: v1.MetricTypeGauge}},
+					}},
+				},
+			},
+		},
+		{
+			description: "deriv() on gauge",
+			content:     "- record: foo\n  expr: deriv(foo[5m])\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeGauge}},
+					}},
+				},
+			},
+		},
+		{
+			description: "delta() on counter",
+			content:     "- record: foo\n  expr: delta(foo[5m])\n",
+			checker:     newCounterCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "delta(foo[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     counterText("prom", uri, "delta", "gauges", "foo", "counter"),
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 15},
+							End:   parser.Position{Line: 2, Column: 17},
+						},
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeCounter}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate() on OpenMetrics counter",
+			content:     "- record: foo\n  expr: rate(foo_total[5m])\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo_total"}},
+					resp:  metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeCounter}},
+					}},
+				},
+			},
+		},
+		{
+			description: "delta() on OpenMetrics counter",
+			content:     "- record: foo\n  expr: delta(foo_total[5m])\n",
+			checker:     newCounterCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "delta(foo_total[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     counterText("prom", uri, "delta", "gauges", "foo_total", "counter"),
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 15},
+							End:   parser.Position{Line: 2, Column: 23},
+						},
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo_total"}},
+					resp:  metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeCounter}},
+					}},
+				},
+			},
+		},
+		{
+			description: "delta() on _total metric with gauge metadata for the base name",
+			content:     "- record: foo\n  expr: delta(foo_total[5m])\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo_total"}},
+					resp:  metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeGauge}},
+					}},
+				},
+			},
+		},
+		{
+			description: "histogram_quantile() on histogram",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newCounterCheck,
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeHistogram}},
+					}},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo_bucket"}},
+					resp:  metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+			},
+		},
+		{
+			description: "histogram_quantile() on summary",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, rate(foo[5m]))\n",
+			checker:     newCounterCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(0.9, rate(foo[5m]))",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     histogramText("prom", uri, "foo", "summary"),
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 38},
+							End:   parser.Position{Line: 2, Column: 40},
+						},
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath, formCond{key: "metric", value: "foo"}},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: v1.MetricTypeSummary}},
+					}},
+				},
+			},
+		},
+		{
+			description: "500 error from Prometheus API",
+			content:     "- record: foo\n  expr: rate(foo[5m]) / rate(bar[5m])\n",
+			checker:     newCounterCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "rate(foo[5m]) / rate(bar[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     checkErrorUnableToRun(checks.CounterCheckName, "prom", uri, "failed to query Prometheus metric metadata: server_error: server error: 500"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "connection refused",
+			content:     "- record: foo\n  expr: rate(foo[5m])\n",
+			checker: func(s string) checks.RuleChecker {
+				return checks.NewCounterCheck(simpleProm("prom", "http://127.0.0.1:1111", time.Second*5, false))
+			},
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "rate(foo[5m])",
+						Lines:    []int{2},
+						Reporter: checks.CounterCheckName,
+						Text:     checkErrorUnableToRun(checks.CounterCheckName, "prom", "http://127.0.0.1:1111", `failed to query Prometheus metric metadata: Get "http://127.0.0.1:1111/api/v1/metadata?limit=&metric=foo": dial tcp 127.0.0.1:1111: connect: connection refused`),
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+	}
+	runTests(t, testCases)
+}
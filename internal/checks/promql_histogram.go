@@ -0,0 +1,187 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/utils"
+)
+
+const (
+	HistogramCheckName = "promql/histogram"
+)
+
+func NewHistogramCheck() HistogramCheck {
+	return HistogramCheck{}
+}
+
+type HistogramCheck struct{}
+
+func (c HistogramCheck) String() string {
+	return HistogramCheckName
+}
+
+func (c HistogramCheck) Reporter() string {
+	return HistogramCheckName
+}
+
+func (c HistogramCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	expr := rule.Expr()
+
+	if expr.SyntaxError != nil {
+		return nil
+	}
+
+	for _, problem := range c.checkNode(expr.Query) {
+		problems = append(problems, Problem{
+			Fragment: problem.expr,
+			Lines:    expr.Lines(),
+			Reporter: c.Reporter(),
+			Text:     problem.text,
+			Severity: problem.severity,
+			Position: exprPosition(expr, problem.node),
+		})
+	}
+
+	return problems
+}
+
+func (c HistogramCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem) {
+	if n, ok := node.Node.(*promParser.Call); ok && n.Func.Name == "histogram_quantile" && len(node.Children) == 2 {
+		problems = append(problems, c.checkQuantile(node, n.Args[0])...)
+
+		for _, agg := range utils.HasOuterAggregation(node.Children[1]) {
+			problems = append(problems, c.checkAggregation(node, agg)...)
+		}
+
+		promParser.Inspect(n.Args[1], func(child promParser.Node, path []promParser.Node) error {
+			vs, ok := child.(*promParser.VectorSelector)
+			if !ok || !strings.HasSuffix(vs.Name, "_bucket") {
+				return nil
+			}
+			problems = append(problems, c.checkSelector(node, vs, path)...)
+			return nil
+		})
+	}
+
+	for _, child := range node.Children {
+		problems = append(problems, c.checkNode(child)...)
+	}
+
+	return problems
+}
+
+// checkQuantile reports quantile values that are known to be outside of [0, 1] range.
+func (c HistogramCheck) checkQuantile(node *parser.PromQLNode, arg promParser.Expr) (problems []exprProblem) {
+	v, ok := numberValue(arg)
+	if !ok || (v >= 0 && v <= 1) {
+		return nil
+	}
+	return []exprProblem{
+		{
+			expr:     node.Expr,
+			text:     fmt.Sprintf("histogram_quantile() quantile argument must be between 0 and 1, got %s", arg),
+			severity: Bug,
+			node:     arg,
+		},
+	}
+}
+
+// checkAggregation reports aggregations that remove the le label
+// which is required to calculate quantiles.
+func (c HistogramCheck) checkAggregation(node *parser.PromQLNode, agg *promParser.AggregateExpr) (problems []exprProblem) {
+	var found bool
+	for _, g := range agg.Grouping {
+		if g == labels.BucketLabel {
+			found = true
+			break
+		}
+	}
+
+	switch {
+	case agg.Without && found:
+		return []exprProblem{
+			{
+				expr:     node.Expr,
+				text:     fmt.Sprintf("%s() is removing the %q label required by histogram_quantile(), remove %s from without()", agg.Op, labels.BucketLabel, labels.BucketLabel),
+				severity: Bug,
+				node:     agg,
+			},
+		}
+	case !agg.Without && !found:
+		return []exprProblem{
+			{
+				expr:     node.Expr,
+				text:     fmt.Sprintf("%s() is removing the %q label required by histogram_quantile(), use by(%s, ...)", agg.Op, labels.BucketLabel, labels.BucketLabel),
+				severity: Bug,
+				node:     agg,
+			},
+		}
+	}
+	return nil
+}
+
+// checkSelector validates _bucket selectors passed to histogram_quantile().
+func (c HistogramCheck) checkSelector(node *parser.PromQLNode, vs *promParser.VectorSelector, path []promParser.Node) (problems []exprProblem) {
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name == labels.BucketLabel {
+			problems = append(problems, exprProblem{
+				expr:     node.Expr,
+				text:     fmt.Sprintf("%s selector is filtering on the %q label, histogram_quantile() needs all buckets to calculate quantiles", vs, labels.BucketLabel),
+				severity: Bug,
+				node:     vs,
+			})
+			break
+		}
+	}
+
+	// recording rules are usually already storing the rate of buckets
+	if !strings.Contains(vs.Name, ":") && !hasRangeFunction(path) {
+		problems = append(problems, exprProblem{
+			expr:     node.Expr,
+			text:     fmt.Sprintf("%s is a cumulative counter and should be wrapped in rate() or increase() before being passed to histogram_quantile()", vs),
+			severity: Warning,
+			node:     vs,
+		})
+	}
+
+	return problems
+}
+
+// hasRangeFunction returns true if any of the parent nodes is a function
+// call that calculates per-second or total increase of a counter.
+func hasRangeFunction(path []promParser.Node) bool {
+	for _, p := range path {
+		if call, ok := p.(*promParser.Call); ok {
+			switch call.Func.Name {
+			case "rate", "irate", "increase":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func numberValue(expr promParser.Expr) (float64, bool) {
+	switch n := expr.(type) {
+	case *promParser.NumberLiteral:
+		return n.Val, true
+	case *promParser.ParenExpr:
+		return numberValue(n.Expr)
+	case *promParser.UnaryExpr:
+		v, ok := numberValue(n.Expr)
+		if ok && n.Op == promParser.SUB {
+			return -v, true
+		}
+		return v, ok
+	case *promParser.StepInvariantExpr:
+		return numberValue(n.Expr)
+	}
+	return 0, false
+}
@@ -0,0 +1,247 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func newHistogramCheck(_ string) checks.RuleChecker {
+	return checks.NewHistogramCheck()
+}
+
+func TestHistogramCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newHistogramCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "ignores queries without histogram_quantile()",
+			content:     "- record: foo\n  expr: sum(rate(foo_bucket[5m]))\n",
+			checker:     newHistogramCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid histogram_quantile()",
+			content:     "- record: foo\n  expr: histogram_quantile(0.99, sum(rate(foo_bucket[5m])) by(le, job))\n",
+			checker:     newHistogramCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid histogram_quantile() without aggregation",
+			content:     "- record: foo\n  expr: histogram_quantile(0.5, rate(foo_bucket{job=\"bar\"}[5m]))\n",
+			checker:     newHistogramCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid histogram_quantile() with without()",
+			content:     "- record: foo\n  expr: histogram_quantile(1, sum(increase(foo_bucket[1h])) without(instance))\n",
+			checker:     newHistogramCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid histogram_quantile() on recording rule",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, job:foo_bucket:rate5m)\n",
+			checker:     newHistogramCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "quantile > 1",
+			content:     "- record: foo\n  expr: histogram_quantile(99, rate(foo_bucket[5m]))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(99, rate(foo_bucket[5m]))",
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     "histogram_quantile() quantile argument must be between 0 and 1, got 99",
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 28},
+							End:   parser.Position{Line: 2, Column: 29},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "quantile < 0",
+			content:     "- record: foo\n  expr: histogram_quantile(-0.5, rate(foo_bucket[5m]))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(-0.5, rate(foo_bucket[5m]))",
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     "histogram_quantile() quantile argument must be between 0 and 1, got -0.5",
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 28},
+							End:   parser.Position{Line: 2, Column: 31},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "sum() without le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(job))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(job))",
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     `sum() is removing the "le" label required by histogram_quantile(), use by(le, ...)`,
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 33},
+							End:   parser.Position{Line: 2, Column: 65},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "sum() without by()",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(0.9, sum(rate(foo_bucket[5m])))",
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     `sum() is removing the "le" label required by histogram_quantile(), use by(le, ...)`,
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 33},
+							End:   parser.Position{Line: 2, Column: 58},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "sum() without(le)",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) without(le))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(0.9, sum(rate(foo_bucket[5m])) without(le))",
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     `sum() is removing the "le" label required by histogram_quantile(), remove le from without()`,
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 33},
+							End:   parser.Position{Line: 2, Column: 69},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "missing rate()",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(foo_bucket) by(le))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "histogram_quantile(0.9, sum(foo_bucket) by(le))",
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     "foo_bucket is a cumulative counter and should be wrapped in rate() or increase() before being passed to histogram_quantile()",
+						Severity: checks.Warning,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 37},
+							End:   parser.Position{Line: 2, Column: 46},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "selector filtering on le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, rate(foo_bucket{le!=\"+Inf\"}[5m]))\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `histogram_quantile(0.9, rate(foo_bucket{le!="+Inf"}[5m]))`,
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     `foo_bucket{le!="+Inf"} selector is filtering on the "le" label, histogram_quantile() needs all buckets to calculate quantiles`,
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 38},
+							End:   parser.Position{Line: 2, Column: 59},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "multiple problems",
+			content:     "- alert: foo\n  expr: histogram_quantile(2, sum(foo_bucket{le=\"1\"}) by(job)) > 1\n",
+			checker:     newHistogramCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `histogram_quantile(2, sum by(job) (foo_bucket{le="1"}))`,
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     "histogram_quantile() quantile argument must be between 0 and 1, got 2",
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 28},
+							End:   parser.Position{Line: 2, Column: 28},
+						},
+					},
+					{
+						Fragment: `histogram_quantile(2, sum by(job) (foo_bucket{le="1"}))`,
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     `sum() is removing the "le" label required by histogram_quantile(), use by(le, ...)`,
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 31},
+							End:   parser.Position{Line: 2, Column: 61},
+						},
+					},
+					{
+						Fragment: `histogram_quantile(2, sum by(job) (foo_bucket{le="1"}))`,
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     `foo_bucket{le="1"} selector is filtering on the "le" label, histogram_quantile() needs all buckets to calculate quantiles`,
+						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 35},
+							End:   parser.Position{Line: 2, Column: 52},
+						},
+					},
+					{
+						Fragment: `histogram_quantile(2, sum by(job) (foo_bucket{le="1"}))`,
+						Lines:    []int{2},
+						Reporter: checks.HistogramCheckName,
+						Text:     "foo_bucket{le=\"1\"} is a cumulative counter and should be wrapped in rate() or increase() before being passed to histogram_quantile()",
+						Severity: checks.Warning,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 35},
+							End:   parser.Position{Line: 2, Column: 52},
+						},
+					},
+				}
+			},
+		},
+	}
+	runTests(t, testCases)
+}
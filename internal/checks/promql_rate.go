@@ -60,6 +60,7 @@ func (c RateCheck) Check(ctx context.Context, rule parser.Rule, entries []discov
 			Reporter: c.Reporter(),
 			Text:     problem.text,
 			Severity: problem.severity,
+			Position: exprPosition(expr, problem.node),
 		})
 	}
 
@@ -83,6 +84,7 @@ func (c RateCheck) checkNode(node *parser.PromQLNode, cfg *promapi.ConfigResult)
 						text: fmt.Sprintf("duration for %s() must be at least %d x scrape_interval, %s is using %s scrape_interval",
 							n.Func.Name, minIntervals, promText(c.prom.Name(), cfg.URI), output.HumanizeDuration(cfg.Config.Global.ScrapeInterval)),
 						severity: Bug,
+						node:     n,
 					}
 					problems = append(problems, p)
 				}
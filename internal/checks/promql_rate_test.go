@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
 )
 
 func newRateCheck(uri string) checks.RuleChecker {
@@ -36,6 +37,10 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "rate", "2", "1m"),
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 21},
+						},
 					},
 				}
 			},
@@ -82,6 +87,10 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "irate", "2", "1m"),
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 22},
+						},
 					},
 				}
 			},
@@ -200,6 +209,10 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "rate", "2", "1m"),
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 34},
+							End:   parser.Position{Line: 2, Column: 46},
+						},
 					},
 				}
 			},
@@ -71,6 +71,7 @@ func (c RegexpCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 						Reporter: c.Reporter(),
 						Text:     fmt.Sprintf(`unnecessary regexp match on static string %s, use %s%s%q instead`, lm, lm.Name, op, lm.Value),
 						Severity: Bug,
+						Position: exprPosition(expr, &selector),
 					})
 				}
 				if beginText > 1 || endText > 1 {
@@ -82,6 +83,7 @@ func (c RegexpCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 							lm, lm.Name, lm.Type, lm.Value,
 						),
 						Severity: Bug,
+						Position: exprPosition(expr, &selector),
 					})
 				}
 			}
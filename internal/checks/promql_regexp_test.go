@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
 )
 
 func newRegexpCheck(_ string) checks.RuleChecker {
@@ -54,6 +55,10 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `unnecessary regexp match on static string job=~"bar", use job="bar" instead`,
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 23},
+						},
 					},
 				}
 			},
@@ -70,6 +75,10 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `unnecessary regexp match on static string job!~"bar", use job!="bar" instead`,
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 23},
+						},
 					},
 				}
 			},
@@ -86,6 +95,10 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `unnecessary regexp match on static string job=~"", use job="" instead`,
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 20},
+						},
 					},
 				}
 			},
@@ -102,6 +115,10 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `prometheus regexp matchers are automatically fully anchored so match for job=~"^.+$" will result in job=~"^^.+$$", remove regexp anchors ^ and/or $`,
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 24},
+						},
 					},
 				}
 			},
@@ -118,6 +135,10 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `prometheus regexp matchers are automatically fully anchored so match for job=~"(foo|^.+)$" will result in job=~"^(foo|^.+)$$", remove regexp anchors ^ and/or $`,
 						Severity: checks.Bug,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 30},
+						},
 					},
 				}
 			},
@@ -136,12 +136,30 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 
 		// 2. If foo was NEVER there -> BUG
 		log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Msg("Checking if base metric has historical series")
-		trs, err := c.serieTimeRanges(ctx, fmt.Sprintf("count(%s)", bareSelector.String()), rangeLookback, rangeStep)
+		lr, err := c.prom.Labels(ctx, []string{bareSelector.String()}, rangeLookback)
 		if err != nil {
 			problems = append(problems, c.queryProblem(err, bareSelector.String(), expr))
 			continue
 		}
-		if len(trs.ranges) == 0 {
+
+		var trs *timeRanges
+		if len(lr.Names) > 0 {
+			// Metric exists, check when it was present
+			log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Msg("Checking base metric time ranges")
+			trs, err = c.serieTimeRanges(ctx, fmt.Sprintf("count(%s)", bareSelector.String()), rangeLookback, rangeStep)
+			if err != nil {
+				problems = append(problems, c.queryProblem(err, bareSelector.String(), expr))
+				continue
+			}
+			if len(trs.ranges) == 0 {
+				// Prometheus older than 2.24 ignores match[] and returns all label names,
+				// if there are no samples then we can't trust that response.
+				log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Msg("Labels API returned names for a metric without any series, match[] is likely unsupported")
+				lr.Names = nil
+			}
+		}
+
+		if len(lr.Names) == 0 {
 			// Check if we have recording rule that provides this metric before we give up
 			var rrEntry *discovery.Entry
 			for _, entry := range entries {
@@ -161,7 +179,7 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 					Lines:    expr.Lines(),
 					Reporter: c.Reporter(),
 					Text: fmt.Sprintf("%s didn't have any series for %q metric in the last %s but found recording rule that generates it, skipping further checks",
-						promText(c.prom.Name(), trs.uri), bareSelector.String(), trs.sinceDesc(trs.from)),
+						promText(c.prom.Name(), lr.URI), bareSelector.String(), sinceDesc(lr.Start)),
 					Severity: Information,
 				})
 				continue
@@ -172,43 +190,28 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 				Lines:    expr.Lines(),
 				Reporter: c.Reporter(),
 				Text: fmt.Sprintf("%s didn't have any series for %q metric in the last %s",
-					promText(c.prom.Name(), trs.uri), bareSelector.String(), trs.sinceDesc(trs.from)),
+					promText(c.prom.Name(), lr.URI), bareSelector.String(), sinceDesc(lr.Start)),
 				Severity: Bug,
 			})
 			log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Msg("No historical series for base metric")
 			continue
 		}
 
-		highChurnLabels := []string{}
-
 		// 3. If foo is ALWAYS/SOMETIMES there BUT {bar OR baz} is NEVER there -> BUG
 		for _, name := range labelNames {
-			l := stripLabels(selector)
-			l.LabelMatchers = append(l.LabelMatchers, labels.MustNewMatcher(labels.MatchRegexp, name, ".+"))
-			log.Debug().Str("check", c.Reporter()).Stringer("selector", &l).Str("label", name).Msg("Checking if base metric has historical series with required label")
-			trsLabelCount, err := c.serieTimeRanges(ctx, fmt.Sprintf("count(%s) by (%s)", l.String(), name), rangeLookback, rangeStep)
-			if err != nil {
-				problems = append(problems, c.queryProblem(err, selector.String(), expr))
+			if hasLabelName(lr.Names, name) {
 				continue
 			}
-
-			labelRanges := trsLabelCount.withLabelName(name)
-			if len(labelRanges) == 0 {
-				problems = append(problems, Problem{
-					Fragment: selector.String(),
-					Lines:    expr.Lines(),
-					Reporter: c.Reporter(),
-					Text: fmt.Sprintf(
-						"%s has %q metric but there are no series with %q label in the last %s",
-						promText(c.prom.Name(), trsLabelCount.uri), bareSelector.String(), name, trsLabelCount.sinceDesc(trsLabelCount.from)),
-					Severity: Bug,
-				})
-				log.Debug().Str("check", c.Reporter()).Stringer("selector", &l).Str("label", name).Msg("No historical series with label used for the query")
-			}
-
-			if len(trsLabelCount.labelValues(name)) == len(trsLabelCount.ranges) && trsLabelCount.avgLife() < (trsLabelCount.duration()/2) {
-				highChurnLabels = append(highChurnLabels, name)
-			}
+			problems = append(problems, Problem{
+				Fragment: selector.String(),
+				Lines:    expr.Lines(),
+				Reporter: c.Reporter(),
+				Text: fmt.Sprintf(
+					"%s has %q metric but there are no series with %q label in the last %s",
+					promText(c.prom.Name(), lr.URI), bareSelector.String(), name, sinceDesc(lr.Start)),
+				Severity: Bug,
+			})
+			log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Str("label", name).Msg("No historical series with label used for the query")
 		}
 		if len(problems) > 0 {
 			continue
@@ -239,8 +242,8 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 				Lines:    expr.Lines(),
 				Reporter: c.Reporter(),
 				Text: fmt.Sprintf(
-					"%s doesn't currently have %q, it was last present %s ago",
-					promText(c.prom.Name(), trs.uri), bareSelector.String(), trs.sinceDesc(trs.newest())),
+					"%s doesn't currently have %q, it was last present %s ago%s",
+					promText(c.prom.Name(), trs.uri), bareSelector.String(), trs.sinceDesc(trs.newest()), incompleteText(trs.missing)),
 				Severity: Bug,
 			})
 			log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Msg("Series disappeared from prometheus")
@@ -264,24 +267,26 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 			}
 			log.Debug().Str("check", c.Reporter()).Stringer("selector", &labelSelector).Stringer("matcher", lm).Msg("Checking if there are historical series matching filter")
 
-			trsLabel, err := c.serieTimeRanges(ctx, fmt.Sprintf("count(%s)", labelSelector.String()), rangeLookback, rangeStep)
+			lvr, err := c.prom.LabelValues(ctx, lm.Name, []string{bareSelector.String()}, rangeLookback)
 			if err != nil {
 				problems = append(problems, c.queryProblem(err, labelSelector.String(), expr))
 				continue
 			}
 
 			// 5. If foo is ALWAYS/SOMETIMES there BUT {bar OR baz} value is NEVER there -> BUG
-			if len(trsLabel.ranges) == 0 {
+			if !hasMatchingValue(lvr.Values, lm) {
 				text := fmt.Sprintf(
 					"%s has %q metric with %q label but there are no series matching {%s} in the last %s",
-					promText(c.prom.Name(), trsLabel.uri), bareSelector.String(), lm.Name, lm.String(), trsLabel.sinceDesc(trs.from))
+					promText(c.prom.Name(), lvr.URI), bareSelector.String(), lm.Name, lm.String(), sinceDesc(lvr.Start))
 				s := Bug
-				for _, name := range highChurnLabels {
-					if lm.Name == name {
-						s = Warning
-						text += fmt.Sprintf(", %q looks like a high churn label", name)
-						break
-					}
+				isHighChurn, err := c.isHighChurnLabel(ctx, bareSelector, lm.Name, rangeLookback, rangeStep)
+				if err != nil {
+					problems = append(problems, c.queryProblem(err, bareSelector.String(), expr))
+					continue
+				}
+				if isHighChurn {
+					s = Warning
+					text += fmt.Sprintf(", %q looks like a high churn label", lm.Name)
 				}
 
 				problems = append(problems, Problem{
@@ -295,6 +300,12 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 				continue
 			}
 
+			trsLabel, err := c.serieTimeRanges(ctx, fmt.Sprintf("count(%s)", labelSelector.String()), rangeLookback, rangeStep)
+			if err != nil {
+				problems = append(problems, c.queryProblem(err, labelSelector.String(), expr))
+				continue
+			}
+
 			// 6. If foo is ALWAYS/SOMETIMES there AND {bar OR baz} used to be there ALWAYS BUT it's NO LONGER there -> BUG
 			if len(trsLabel.ranges) == 1 &&
 				!trsLabel.oldest().After(trsLabel.until.Add(rangeLookback-1).Add(rangeStep)) &&
@@ -320,8 +331,8 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 					Lines:    expr.Lines(),
 					Reporter: c.Reporter(),
 					Text: fmt.Sprintf(
-						"%s has %q metric but doesn't currently have series matching {%s}, such series was last present %s ago",
-						promText(c.prom.Name(), trs.uri), bareSelector.String(), lm.String(), trsLabel.sinceDesc(trsLabel.newest())),
+						"%s has %q metric but doesn't currently have series matching {%s}, such series was last present %s ago%s",
+						promText(c.prom.Name(), trs.uri), bareSelector.String(), lm.String(), trsLabel.sinceDesc(trsLabel.newest()), incompleteText(trsLabel.missing)),
 					Severity: Bug,
 				})
 				log.Debug().Str("check", c.Reporter()).Stringer("selector", &selector).Stringer("matcher", lm).Msg("Series matching filter disappeared from prometheus ")
@@ -335,9 +346,9 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 					Lines:    expr.Lines(),
 					Reporter: c.Reporter(),
 					Text: fmt.Sprintf(
-						"metric %q with label {%s} is only sometimes present on %s with average life span of %s",
+						"metric %q with label {%s} is only sometimes present on %s with average life span of %s%s",
 						bareSelector.String(), lm.String(), promText(c.prom.Name(), trs.uri),
-						output.HumanizeDuration(trsLabel.avgLife())),
+						output.HumanizeDuration(trsLabel.avgLife()), incompleteText(trsLabel.missing)),
 					Severity: Warning,
 				})
 				log.Debug().Str("check", c.Reporter()).Stringer("selector", &selector).Stringer("matcher", lm).Msg("Series matching filter are only sometimes present")
@@ -354,8 +365,8 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 				Lines:    expr.Lines(),
 				Reporter: c.Reporter(),
 				Text: fmt.Sprintf(
-					"metric %q is only sometimes present on %s with average life span of %s in the last %s",
-					bareSelector.String(), promText(c.prom.Name(), trs.uri), output.HumanizeDuration(trs.avgLife()), trs.sinceDesc(trs.from)),
+					"metric %q is only sometimes present on %s with average life span of %s in the last %s%s",
+					bareSelector.String(), promText(c.prom.Name(), trs.uri), output.HumanizeDuration(trs.avgLife()), trs.sinceDesc(trs.from), incompleteText(trs.missing)),
 				Severity: Warning,
 			})
 			log.Debug().Str("check", c.Reporter()).Stringer("selector", &bareSelector).Msg("Metric only sometimes present")
@@ -397,10 +408,11 @@ func (c SeriesCheck) serieTimeRanges(ctx context.Context, query string, lookback
 	}
 
 	tr = &timeRanges{
-		uri:   qr.URI,
-		from:  qr.Start,
-		until: qr.End,
-		step:  step,
+		uri:     qr.URI,
+		from:    qr.Start,
+		until:   qr.End,
+		step:    step,
+		missing: qr.Missing(),
 	}
 	var ts time.Time
 	for _, s := range qr.Samples {
@@ -430,6 +442,21 @@ func (c SeriesCheck) serieTimeRanges(ctx context.Context, query string, lookback
 	return tr, nil
 }
 
+// isHighChurnLabel returns true if every value of given label is only present
+// for a short time.
+func (c SeriesCheck) isHighChurnLabel(ctx context.Context, selector promParser.VectorSelector, name string, lookback, step time.Duration) (bool, error) {
+	l := stripLabels(selector)
+	l.LabelMatchers = append(l.LabelMatchers, labels.MustNewMatcher(labels.MatchRegexp, name, ".+"))
+	log.Debug().Str("check", c.Reporter()).Stringer("selector", &l).Str("label", name).Msg("Checking if label is a high churn label")
+	trs, err := c.serieTimeRanges(ctx, fmt.Sprintf("count(%s) by (%s)", l.String(), name), lookback, step)
+	if err != nil {
+		return false, err
+	}
+	return len(trs.withLabelName(name)) > 0 &&
+		len(trs.labelValues(name)) == len(trs.ranges) &&
+		trs.avgLife() < (trs.duration()/2), nil
+}
+
 func (c SeriesCheck) getMinAge(rule parser.Rule, selector promParser.VectorSelector) (minAge time.Duration, problems []Problem) {
 	minAge = time.Hour * 2
 
@@ -472,12 +499,35 @@ func (c SeriesCheck) isLabelValueIgnored(rule parser.Rule, selector promParser.V
 	return false
 }
 
+func hasLabelName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMatchingValue(values []string, lm *labels.Matcher) bool {
+	// series without this label would match too
+	if lm.Matches("") {
+		return true
+	}
+	for _, v := range values {
+		if lm.Matches(v) {
+			return true
+		}
+	}
+	return false
+}
+
 func getSelectors(n *parser.PromQLNode) (selectors []promParser.VectorSelector) {
 	if node, ok := n.Node.(*promParser.VectorSelector); ok {
 		// copy node without offset
 		nc := promParser.VectorSelector{
 			Name:          node.Name,
 			LabelMatchers: node.LabelMatchers,
+			PosRange:      node.PosRange,
 		}
 		selectors = append(selectors, nc)
 	}
@@ -510,11 +560,12 @@ type timeRange struct {
 }
 
 type timeRanges struct {
-	uri    string
-	from   time.Time
-	until  time.Time
-	step   time.Duration
-	ranges []timeRange
+	uri     string
+	from    time.Time
+	until   time.Time
+	step    time.Duration
+	missing time.Duration
+	ranges  []timeRange
 }
 
 func (tr timeRanges) withLabelName(name string) (r []timeRange) {
@@ -576,6 +627,10 @@ func (tr timeRanges) newest() (ts time.Time) {
 }
 
 func (tr timeRanges) sinceDesc(t time.Time) (s string) {
+	return sinceDesc(t)
+}
+
+func sinceDesc(t time.Time) (s string) {
 	dur := time.Since(t)
 	if dur > time.Hour*24 {
 		return output.HumanizeDuration(dur.Round(time.Hour))
@@ -127,8 +127,10 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{
+						requireLabelsPath,
+					},
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -157,10 +159,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(notfound)"},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 				{
 					conds: []requestCondition{requireQueryPath, formCond{key: "query", value: "count(found_7)"}},
@@ -296,8 +298,47 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{
+						requireLabelsPath,
+					},
+					resp: labelsResponse{},
+				},
+			},
+		},
+		{
+			description: "#2 series never present, match[] not supported",
+			content:     "- record: foo\n  expr: sum(notfound)\n",
+			checker:     newSeriesCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "notfound",
+						Lines:    []int{2},
+						Reporter: checks.SeriesCheckName,
+						Text:     noMetricText("prom", uri, "notfound", "1w"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "notfound"},
+					},
+					// old Prometheus versions ignore match[] and return all label names
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `count(notfound)`},
+					},
+					resp: respondWithEmptyMatrix(),
 				},
 			},
 		},
@@ -327,10 +368,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:bar)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo:bar"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -360,10 +401,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:bar)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo:bar"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -427,10 +468,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:bar)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo:bar"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -444,7 +485,7 @@ func TestSeriesCheck(t *testing.T) {
 						Fragment: `found`,
 						Lines:    []int{2},
 						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: server error: 500"),
+						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "failed to query Prometheus label names: server_error: server error: 500"),
 						Severity: checks.Bug,
 					},
 				}
@@ -455,8 +496,10 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithInternalError(),
+					conds: []requestCondition{
+						requireLabelsPath,
+					},
+					resp: respondWithInternalError(),
 				},
 			},
 		},
@@ -485,20 +528,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: labelsResponse{values: []string{"__name__", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"job": "xxx"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now(),
 								time.Minute*5,
@@ -506,13 +549,6 @@ func TestSeriesCheck(t *testing.T) {
 						},
 					},
 				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{notfound=~".+"}) by (notfound)`},
-					},
-					resp: respondWithSingleRangeVector1W(),
-				},
 			},
 		},
 		{
@@ -522,10 +558,10 @@ func TestSeriesCheck(t *testing.T) {
 			problems: func(uri string) []checks.Problem {
 				return []checks.Problem{
 					{
-						Fragment: `found{notfound="xxx"}`,
+						Fragment: `found`,
 						Lines:    []int{2},
 						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: server error: 500"),
+						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "failed to query Prometheus label names: server_error: server error: 500"),
 						Severity: checks.Bug,
 					},
 				}
@@ -540,15 +576,8 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: respondWithSingleRangeVector1W(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{notfound=~".+"}) by (notfound)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
 					resp: respondWithInternalError(),
 				},
@@ -569,45 +598,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Minute*-50),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Minute*-50),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Minute*-50),
 								time.Minute*5,
@@ -642,45 +646,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
@@ -709,45 +688,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
@@ -776,45 +730,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
@@ -853,45 +782,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
@@ -930,45 +834,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
@@ -1014,45 +893,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job=~".+"}) by (job)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"job": "foo"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"__name__", "instance", "job"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"instance": "bar"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
@@ -1087,36 +941,27 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: labelsResponse{values: []string{"__name__", "instance", "not", "notfound"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"}) by (instance)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"instance": "bar"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						formCond{key: "query", value: `count(found)`},
 					},
+					resp: respondWithSingleRangeVector1W(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{not=~".+"}) by (not)`},
+						formCond{key: "query", value: `count(found{notfound=~".+"}) by (notfound)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"not": "yyy"},
+								map[string]string{"notfound": "found"},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now(),
 								time.Minute*5,
@@ -1126,19 +971,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{notfound=~".+"}) by (notfound)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"notfound": "found"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("instance"),
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"bar"}},
 				},
 				{
 					conds: []requestCondition{
@@ -1158,10 +994,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{notfound="notfound"})`},
+						requireLabelValuesPath("notfound"),
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -1188,6 +1024,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "error"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1197,19 +1040,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{error=~".+"}) by (error)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"error": "bar"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("error"),
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"xxx"}},
 				},
 				{
 					conds: []requestCondition{
@@ -1243,6 +1077,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "sometimes"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "churn"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1301,10 +1142,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(sometimes{churn="notfound"})`},
+						requireLabelValuesPath("churn"),
+						formCond{key: "match[]", value: "sometimes"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -1327,26 +1168,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: labelsResponse{values: []string{"__name__", "error"}},
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo{error=~".+"}) by (error)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"error": "yyy"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						formCond{key: "query", value: `count(foo)`},
 					},
+					resp: respondWithSingleRangeVector1W(),
 				},
 			},
 		},
@@ -1373,6 +1205,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "removed"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1382,19 +1221,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{removed=~".+"}) by (removed)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"removed": "xxx"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-6).Add(time.Hour*8),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("removed"),
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"xxx"}},
 				},
 				{
 					conds: []requestCondition{
@@ -1448,6 +1278,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "removed"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1457,19 +1294,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{removed=~".+"}) by (removed)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"removed": "xxx"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-6).Add(time.Hour*8),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("removed"),
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"xxx"}},
 				},
 				{
 					conds: []requestCondition{
@@ -1506,6 +1334,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "removed"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1515,19 +1350,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{removed=~".+"}) by (removed)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"removed": "xxx"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Minute*-150),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("removed"),
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"xxx"}},
 				},
 				{
 					conds: []requestCondition{
@@ -1570,6 +1396,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "sometimes"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1579,43 +1412,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{sometimes=~".+"}) by (sometimes)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"sometimes": "aaa"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now(),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{"sometimes": "bbb"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{"sometimes": "xxx"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-6).Add(time.Hour*8),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{"sometimes": "xxx"},
-								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-4),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{"sometimes": "xxx"},
-								time.Now().Add(time.Hour*24*-2),
-								time.Now().Add(time.Hour*24*-2),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("sometimes"),
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: labelsResponse{values: []string{"xxx"}},
 				},
 				{
 					conds: []requestCondition{
@@ -1670,6 +1470,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "sometimes"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "foo"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1698,27 +1505,61 @@ func TestSeriesCheck(t *testing.T) {
 						},
 					},
 				},
+			},
+		},
+		{
+			description: "#8 metric is sometimes present, some queries failed",
+			content:     "- record: foo\n  expr: sum(sometimes{foo!=\"bar\"})\n",
+			checker:     newSeriesCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `sometimes`,
+						Lines:    []int{2},
+						Reporter: checks.SeriesCheckName,
+						Text:     seriesSometimesText("prom", uri, "sometimes", "1w", "20m") + ", results might be incomplete because queries for 1d of that time range failed",
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sometimes{foo!="bar"})`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "sometimes"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "foo"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(sometimes{foo=~".+"}) by (foo)`},
+						formCond{key: "query", value: `count(sometimes)`},
+						rangeStartCond{age: time.Hour * 24 * 6},
+					},
+					resp: respondWithTooManySamples(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `count(sometimes)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"foo": "aaa"},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-7).Add(time.Hour),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{"foo": "bbb"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-5),
 								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
 								time.Minute*5,
 							),
 							generateSampleStream(
-								map[string]string{"foo": "ccc"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-2),
 								time.Now().Add(time.Hour*24*-2).Add(time.Minute*20),
 								time.Minute*5,
@@ -1751,6 +1592,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: labelsResponse{values: []string{"__name__", "job"}},
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1776,10 +1624,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job="notfound"})`},
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -1808,10 +1656,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(notfound)"},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -1843,10 +1691,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(notfound)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -1909,10 +1757,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(notfound)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
@@ -1949,12 +1797,11 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:count)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo:count"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
-
 				{
 					conds: []requestCondition{
 						requireQueryPath,
@@ -1964,10 +1811,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:sum)`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo:sum"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: labelsResponse{},
 				},
 			},
 		},
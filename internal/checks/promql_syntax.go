@@ -35,6 +35,7 @@ func (c SyntaxCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 			Reporter: c.Reporter(),
 			Text:     fmt.Sprintf("syntax error: %s", q.SyntaxError),
 			Severity: Fatal,
+			Position: q.SyntaxErrorRange(),
 		})
 	}
 	return
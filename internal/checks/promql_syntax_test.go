@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
 )
 
 func newSyntaxCheck(_ string) checks.RuleChecker {
@@ -36,6 +37,10 @@ func TestSyntaxCheck(t *testing.T) {
 						Reporter: "promql/syntax",
 						Text:     "syntax error: no arguments for aggregate expression provided",
 						Severity: checks.Fatal,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 9},
+							End:   parser.Position{Line: 2, Column: 12},
+						},
 					},
 				}
 			},
@@ -52,6 +57,10 @@ func TestSyntaxCheck(t *testing.T) {
 						Reporter: "promql/syntax",
 						Text:     "syntax error: unclosed left parenthesis",
 						Severity: checks.Fatal,
+						Position: &parser.PositionRange{
+							Start: parser.Position{Line: 2, Column: 20},
+							End:   parser.Position{Line: 2, Column: 20},
+						},
 					},
 				}
 			},
@@ -30,6 +30,9 @@ type VectorMatchingCheck struct {
 }
 
 func (c VectorMatchingCheck) String() string {
+	if c.prom == nil {
+		return VectorMatchingCheckName
+	}
 	return fmt.Sprintf("%s(%s)", VectorMatchingCheckName, c.prom.Name())
 }
 
@@ -43,7 +46,8 @@ func (c VectorMatchingCheck) Check(ctx context.Context, rule parser.Rule, entrie
 		return nil
 	}
 
-	for _, problem := range c.checkNode(ctx, expr.Query) {
+	rules := recordingRules(entries)
+	for _, problem := range c.checkNode(ctx, expr.Query, rules) {
 		problems = append(problems, Problem{
 			Fragment: problem.expr,
 			Lines:    expr.Lines(),
@@ -56,7 +60,26 @@ func (c VectorMatchingCheck) Check(ctx context.Context, rule parser.Rule, entrie
 	return
 }
 
-func (c VectorMatchingCheck) checkNode(ctx context.Context, node *parser.PromQLNode) (problems []exprProblem) {
+func (c VectorMatchingCheck) checkNode(ctx context.Context, node *parser.PromQLNode, rules utils.RecordingRules) (problems []exprProblem) {
+	// if both sides use recording rules from the repository then we can
+	// compare labels without querying Prometheus, which might not have these
+	// rules deployed yet
+	if n, ok := node.Node.(*promParser.BinaryExpr); ok &&
+		n.VectorMatching != nil &&
+		n.Op != promParser.LOR &&
+		n.Op != promParser.LUNLESS {
+		if lhs, rhs, ok := repositoryLabels(n, rules); ok {
+			if c.prom == nil {
+				problems = append(problems, c.compareLabels(node, n, lhs, rhs)...)
+			}
+			goto NEXT
+		}
+	}
+
+	if c.prom == nil {
+		goto NEXT
+	}
+
 	if n, ok := utils.RemoveConditions(node.Node.String()).(*promParser.BinaryExpr); ok &&
 		n.VectorMatching != nil &&
 		n.Op != promParser.LOR &&
@@ -192,12 +215,60 @@ func (c VectorMatchingCheck) checkNode(ctx context.Context, node *parser.PromQLN
 
 NEXT:
 	for _, child := range node.Children {
-		problems = append(problems, c.checkNode(ctx, child)...)
+		problems = append(problems, c.checkNode(ctx, child, rules)...)
 	}
 
 	return
 }
 
+func (c VectorMatchingCheck) compareLabels(node *parser.PromQLNode, n *promParser.BinaryExpr, lhs, rhs utils.LabelSet) (problems []exprProblem) {
+	if n.VectorMatching.On {
+		for _, name := range n.VectorMatching.MatchingLabels {
+			switch {
+			case !lhs.CanHave(name) && rhs.CanHave(name):
+				problems = append(problems, exprProblem{
+					expr:     node.Expr,
+					text:     fmt.Sprintf("using on(%q) won't produce any results because left hand side of the query doesn't have this label: %q", name, n.LHS),
+					severity: Bug,
+				})
+			case lhs.CanHave(name) && !rhs.CanHave(name):
+				problems = append(problems, exprProblem{
+					expr:     node.Expr,
+					text:     fmt.Sprintf("using on(%q) won't produce any results because right hand side of the query doesn't have this label: %q", name, n.RHS),
+					severity: Bug,
+				})
+			case !lhs.CanHave(name) && !rhs.CanHave(name):
+				problems = append(problems, exprProblem{
+					expr:     node.Expr,
+					text:     fmt.Sprintf("using on(%q) won't produce any results because both sides of the query don't have this label", name),
+					severity: Bug,
+				})
+			}
+		}
+		return
+	}
+
+	leftLabels := ignoreLabels(lhs.Possible, n.VectorMatching.MatchingLabels)
+	rightLabels := ignoreLabels(rhs.Possible, n.VectorMatching.MatchingLabels)
+	if areStringSlicesEqual(leftLabels, rightLabels) {
+		return
+	}
+	if len(n.VectorMatching.MatchingLabels) == 0 {
+		problems = append(problems, exprProblem{
+			expr:     node.Expr,
+			text:     fmt.Sprintf("both sides of the query have different labels: %s != %s", leftLabels, rightLabels),
+			severity: Bug,
+		})
+	} else {
+		problems = append(problems, exprProblem{
+			expr:     node.Expr,
+			text:     fmt.Sprintf("using ignoring(%q) won't produce any results because both sides of the query have different labels: %s != %s", strings.Join(n.VectorMatching.MatchingLabels, ","), leftLabels, rightLabels),
+			severity: Bug,
+		})
+	}
+	return
+}
+
 func (c VectorMatchingCheck) seriesLabels(ctx context.Context, query string, ignored ...model.LabelName) ([]string, error) {
 	qr, err := c.prom.Query(ctx, query)
 	if err != nil {
@@ -239,3 +310,46 @@ func stringInSlice(sl []string, v string) bool {
 func areStringSlicesEqual(sla, slb []string) bool {
 	return reflect.DeepEqual(sla, slb)
 }
+
+// recordingRules returns all recording rules defined in the repository.
+func recordingRules(entries []discovery.Entry) utils.RecordingRules {
+	rules := make([]parser.Rule, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PathError == nil {
+			rules = append(rules, entry.Rule)
+		}
+	}
+	return utils.NewRecordingRules(rules)
+}
+
+// repositoryLabels returns labels of both sides of a binary expression if
+// it uses any recording rule from the repository and labels on both sides
+// are fully known.
+func repositoryLabels(n *promParser.BinaryExpr, rules utils.RecordingRules) (lhs, rhs utils.LabelSet, ok bool) {
+	var usesRules bool
+	promParser.Inspect(n, func(node promParser.Node, _ []promParser.Node) error {
+		if vs, ok := node.(*promParser.VectorSelector); ok {
+			if _, found := rules[vs.Name]; found {
+				usesRules = true
+			}
+		}
+		return nil
+	})
+	if !usesRules {
+		return lhs, rhs, false
+	}
+
+	lhs = utils.OutputLabels(&parser.PromQLNode{Node: n.LHS}, rules)
+	rhs = utils.OutputLabels(&parser.PromQLNode{Node: n.RHS}, rules)
+	return lhs, rhs, lhs.Fixed && rhs.Fixed
+}
+
+func ignoreLabels(names, ignored []string) []string {
+	kept := []string{}
+	for _, name := range names {
+		if !stringInSlice(ignored, name) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
@@ -14,6 +14,21 @@ func newVectorMatchingCheck(uri string) checks.RuleChecker {
 	return checks.NewVectorMatchingCheck(simpleProm("prom", uri, time.Second, true))
 }
 
+func newVectorMatchingCheckOffline(_ string) checks.RuleChecker {
+	return checks.NewVectorMatchingCheck(nil)
+}
+
+const vectorMatchingRules = `
+- record: job:foo:sum
+  expr: sum(foo) by(job)
+- record: job:bar:sum
+  expr: sum(bar) by(job)
+  labels:
+    source: bar
+- record: instance:foo:sum
+  expr: sum(foo) by(job, instance)
+`
+
 func differentLabelsText(l, r string) string {
 	return fmt.Sprintf(`both sides of the query have different labels: [%s] != [%s]`, l, r)
 }
@@ -1234,6 +1249,102 @@ func TestVectorMatchingCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "offline / ignores queries without recording rules",
+			content:     "- record: foo\n  expr: sum(foo) by(job) / sum(bar) by(instance)\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems:    noProblems,
+		},
+		{
+			description: "offline / ignores raw metrics",
+			content:     "- record: foo\n  expr: job:foo:sum / bar\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems:    noProblems,
+		},
+		{
+			description: "offline / matching recording rules",
+			content:     "- record: foo\n  expr: job:foo:sum / ignoring(source) job:bar:sum\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems:    noProblems,
+		},
+		{
+			description: "offline / different labels",
+			content:     "- record: foo\n  expr: instance:foo:sum / job:foo:sum\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "instance:foo:sum / job:foo:sum",
+						Lines:    []int{2},
+						Reporter: checks.VectorMatchingCheckName,
+						Text:     differentLabelsText("instance job", "job"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "offline / different labels with ignoring()",
+			content:     "- record: foo\n  expr: job:foo:sum / ignoring(instance) job:bar:sum\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "job:foo:sum / ignoring(instance) job:bar:sum",
+						Lines:    []int{2},
+						Reporter: checks.VectorMatchingCheckName,
+						Text:     usingMismatchText(`ignoring("instance")`, "job", "job source"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "offline / on() with a label missing on one side",
+			content:     "- record: foo\n  expr: instance:foo:sum / on(instance) sum(job:foo:sum) by(job)\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "instance:foo:sum / on(instance) sum(job:foo:sum) by(job)",
+						Lines:    []int{2},
+						Reporter: checks.VectorMatchingCheckName,
+						Text:     `using on("instance") won't produce any results because right hand side of the query doesn't have this label: "sum by(job) (job:foo:sum)"`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "offline / on() with a label missing on both sides",
+			content:     "- alert: foo\n  expr: job:foo:sum > on(env) job:bar:sum\n",
+			checker:     newVectorMatchingCheckOffline,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "job:foo:sum > on(env) job:bar:sum",
+						Lines:    []int{2},
+						Reporter: checks.VectorMatchingCheckName,
+						Text:     `using on("env") won't produce any results because both sides of the query don't have this label`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "online / skips queries using recording rules",
+			content:     "- record: foo\n  expr: instance:foo:sum / job:foo:sum\n",
+			checker:     newVectorMatchingCheck,
+			entries:     mustParseContent(vectorMatchingRules),
+			problems:    noProblems,
+		},
 	}
 	runTests(t, testCases)
 }
@@ -0,0 +1,136 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	RuleDependencyCheckName = "rule/dependency"
+
+	// Prometheus default for groups without interval
+	defaultEvaluationInterval = time.Minute
+)
+
+func NewRuleDependencyCheck() RuleDependencyCheck {
+	return RuleDependencyCheck{}
+}
+
+type RuleDependencyCheck struct{}
+
+func (c RuleDependencyCheck) String() string {
+	return RuleDependencyCheckName
+}
+
+func (c RuleDependencyCheck) Reporter() string {
+	return RuleDependencyCheckName
+}
+
+func (c RuleDependencyCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	g := graph.FromContext(ctx, entries)
+	node := g.Node(rule)
+	if node == nil {
+		return
+	}
+
+	expr := rule.Expr()
+
+	if cycle := g.Cycle(node); cycle != nil {
+		names := make([]string, 0, len(cycle))
+		for _, n := range cycle {
+			names = append(names, n.Name())
+		}
+		problems = append(problems, Problem{
+			Fragment: node.Name(),
+			Lines:    expr.Lines(),
+			Reporter: c.Reporter(),
+			Text:     fmt.Sprintf("recording rule %q is part of a dependency cycle: %s", node.Name(), strings.Join(names, " -> ")),
+			Severity: Bug,
+		})
+	}
+
+	seen := map[*graph.Node]bool{}
+	for _, dep := range node.Dependencies {
+		if seen[dep.Node] || dep.Node == node {
+			continue
+		}
+		seen[dep.Node] = true
+
+		if node.SameGroup(dep.Node) {
+			problems = append(problems, c.checkOrder(expr, node, dep)...)
+			continue
+		}
+		problems = append(problems, c.checkInterval(expr, node, dep)...)
+	}
+
+	return
+}
+
+func (c RuleDependencyCheck) checkOrder(expr parser.PromQLExpr, node *graph.Node, dep graph.Dependency) (problems []Problem) {
+	if node.Rule.AlertingRule == nil {
+		return
+	}
+	if dep.Node.Rule.Lines()[0] < node.Rule.Lines()[0] {
+		return
+	}
+
+	problems = append(problems, Problem{
+		Fragment: dep.Selector.String(),
+		Lines:    expr.Lines(),
+		Reporter: c.Reporter(),
+		Text: fmt.Sprintf("alerting rule %q uses recording rule %q which is defined later in the same group, rules are evaluated in order so this alert will always use results from the previous evaluation",
+			node.Name(), dep.Node.Name()),
+		Severity: Warning,
+		Position: exprPosition(expr, dep.Selector),
+	})
+	return
+}
+
+func (c RuleDependencyCheck) checkInterval(expr parser.PromQLExpr, node *graph.Node, dep graph.Dependency) (problems []Problem) {
+	if node.Rule.Group == nil || dep.Node.Rule.Group == nil {
+		return
+	}
+
+	interval, ok := groupInterval(node.Rule.Group)
+	if !ok {
+		return
+	}
+	depInterval, ok := groupInterval(dep.Node.Rule.Group)
+	if !ok || depInterval <= interval {
+		return
+	}
+
+	problems = append(problems, Problem{
+		Fragment: dep.Selector.String(),
+		Lines:    expr.Lines(),
+		Reporter: c.Reporter(),
+		Text: fmt.Sprintf("recording rule %q is evaluated every %s in group %q defined in %s, which is less often than this group interval %s, results of this rule will lag behind by up to %s",
+			dep.Node.Name(), output.HumanizeDuration(depInterval), dep.Node.Rule.Group.Name.Value.Value, dep.Node.Path,
+			output.HumanizeDuration(interval), output.HumanizeDuration(depInterval)),
+		Severity: Warning,
+		Position: exprPosition(expr, dep.Selector),
+	})
+	return
+}
+
+// groupInterval returns evaluation interval of a group, groups without
+// interval are assumed to use Prometheus default.
+func groupInterval(group *parser.RuleGroup) (time.Duration, bool) {
+	if group.Interval == nil {
+		return defaultEvaluationInterval, true
+	}
+	interval, err := model.ParseDuration(group.Interval.Value.Value)
+	if err != nil || interval == 0 {
+		return 0, false
+	}
+	return time.Duration(interval), true
+}
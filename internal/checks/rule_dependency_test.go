@@ -0,0 +1,178 @@
+package checks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestRuleDependencyCheck(t *testing.T) {
+	type testCaseT struct {
+		description string
+		entries     []discovery.Entry
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no dependencies",
+			entries:     mustParseContent("- record: foo\n  expr: sum(up)\n- alert: bar\n  expr: up == 0\n"),
+		},
+		{
+			description: "dependency cycle",
+			entries: mustParseContent(`
+- record: foo
+  expr: sum(bar)
+- record: bar
+  expr: sum(foo)
+- alert: foo
+  expr: foo == 0
+`),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{3},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `recording rule "foo" is part of a dependency cycle: foo -> bar -> foo`,
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "bar",
+					Lines:    []int{5},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `recording rule "bar" is part of a dependency cycle: bar -> foo -> bar`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "self reference",
+			entries:     mustParseContent("- record: foo\n  expr: foo + 1\n"),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{2},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `recording rule "foo" is part of a dependency cycle: foo -> foo`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "alert uses recording rule defined later in the same group",
+			entries: mustParseContent(`
+groups:
+- name: foo
+  rules:
+  - alert: foo
+    expr: foo:sum == 0
+  - record: foo:sum
+    expr: sum(foo)
+  - alert: bar
+    expr: foo:sum == 0
+`),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo:sum",
+					Lines:    []int{6},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `alerting rule "foo" uses recording rule "foo:sum" which is defined later in the same group, rules are evaluated in order so this alert will always use results from the previous evaluation`,
+					Severity: checks.Warning,
+					Position: &parser.PositionRange{
+						Start: parser.Position{Line: 6, Column: 11},
+						End:   parser.Position{Line: 6, Column: 17},
+					},
+				},
+			},
+		},
+		{
+			description: "recording rule uses recording rule defined later in the same group",
+			entries: mustParseContent(`
+groups:
+- name: foo
+  rules:
+  - record: foo:count
+    expr: count(foo:sum)
+  - record: foo:sum
+    expr: sum(foo)
+`),
+		},
+		{
+			description: "recording rule from a slower group",
+			entries: mustParseContent(`
+groups:
+- name: fast
+  interval: 30s
+  rules:
+  - alert: foo
+    expr: foo:sum == 0
+  - alert: bar
+    expr: bar:sum == 0
+- name: slow
+  interval: 5m
+  rules:
+  - record: foo:sum
+    expr: sum(foo)
+- name: default
+  rules:
+  - record: bar:sum
+    expr: sum(bar)
+  - alert: foo
+    expr: foo:sum == 0
+  - alert: bar
+    expr: bar:sum == 0
+`),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo:sum",
+					Lines:    []int{7},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `recording rule "foo:sum" is evaluated every 5m in group "slow" defined in fake.yml, which is less often than this group interval 30s, results of this rule will lag behind by up to 5m`,
+					Severity: checks.Warning,
+					Position: &parser.PositionRange{
+						Start: parser.Position{Line: 7, Column: 11},
+						End:   parser.Position{Line: 7, Column: 17},
+					},
+				},
+				{
+					Fragment: "bar:sum",
+					Lines:    []int{9},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `recording rule "bar:sum" is evaluated every 1m in group "default" defined in fake.yml, which is less often than this group interval 30s, results of this rule will lag behind by up to 1m`,
+					Severity: checks.Warning,
+					Position: &parser.PositionRange{
+						Start: parser.Position{Line: 9, Column: 11},
+						End:   parser.Position{Line: 9, Column: 17},
+					},
+				},
+				{
+					Fragment: "foo:sum",
+					Lines:    []int{20},
+					Reporter: checks.RuleDependencyCheckName,
+					Text:     `recording rule "foo:sum" is evaluated every 5m in group "slow" defined in fake.yml, which is less often than this group interval 1m, results of this rule will lag behind by up to 5m`,
+					Severity: checks.Warning,
+					Position: &parser.PositionRange{
+						Start: parser.Position{Line: 20, Column: 11},
+						End:   parser.Position{Line: 20, Column: 17},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			c := checks.NewRuleDependencyCheck()
+			var problems []checks.Problem
+			for _, entry := range tc.entries {
+				problems = append(problems, c.Check(context.Background(), entry.Rule, tc.entries)...)
+			}
+			require.Equal(t, tc.problems, problems)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	RuleDuplicateCheckName = "rule/duplicate"
+)
+
+// NewRuleDuplicateCheck creates a new rule/duplicate check. The servers
+// function should return names of all Prometheus servers given rule would be
+// deployed to, only rules deployed to at least one common server are compared.
+// If it's nil then all rules are compared.
+func NewRuleDuplicateCheck(servers func(path string, rule parser.Rule) []string) RuleDuplicateCheck {
+	return RuleDuplicateCheck{servers: servers}
+}
+
+type RuleDuplicateCheck struct {
+	servers func(path string, rule parser.Rule) []string
+}
+
+func (c RuleDuplicateCheck) String() string {
+	return RuleDuplicateCheckName
+}
+
+func (c RuleDuplicateCheck) Reporter() string {
+	return RuleDuplicateCheckName
+}
+
+func (c RuleDuplicateCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	name, kind := ruleNameAndKind(rule)
+	if name == "" {
+		return
+	}
+
+	var path string
+	for _, entry := range entries {
+		if isEntryForRule(entry, rule) {
+			path = entry.Path
+			break
+		}
+	}
+
+	labels := ruleLabels(rule)
+	expr := normalisedExpr(rule)
+	for _, entry := range entries {
+		if entry.PathError != nil || entry.Rule.Error.Err != nil || isEntryForRule(entry, rule) {
+			continue
+		}
+		if n, k := ruleNameAndKind(entry.Rule); n != name || k != kind {
+			continue
+		}
+		if entry.Path == path && entry.Rule.Document == rule.Document &&
+			(sameGroup(entry.Rule.Group, rule.Group) || (entry.Rule.Group == nil && rule.Group == nil)) {
+			// duplicates inside a single group are reported by rule/group,
+			// rules without any group in the same file are treated as one group
+			continue
+		}
+		if !sameLabels(ruleLabels(entry.Rule), labels) {
+			continue
+		}
+		if c.servers != nil && !shareServers(c.servers(path, rule), c.servers(entry.Path, entry.Rule)) {
+			continue
+		}
+
+		severity := Bug
+		text := fmt.Sprintf("%s rule %q with identical labels and query is also defined in %s on line %d",
+			kind, name, entry.Path, entry.Rule.Lines()[0])
+		if normalisedExpr(entry.Rule) != expr {
+			severity = Warning
+			text = fmt.Sprintf("%s rule %q with identical labels but a different query is also defined in %s on line %d",
+				kind, name, entry.Path, entry.Rule.Lines()[0])
+		}
+		if rule.RecordingRule != nil {
+			text += ", both rules will produce the same time series"
+		} else {
+			text += ", both rules will produce alerts with the same labels"
+		}
+		problems = append(problems, Problem{
+			Fragment: name,
+			Lines:    rule.Lines(),
+			Reporter: c.Reporter(),
+			Text:     text,
+			Severity: severity,
+		})
+	}
+
+	return
+}
+
+// isEntryForRule returns true if given entry holds the rule being checked.
+// Rules are passed to checks as copies of entries, so the rule bodies are
+// shared and can be compared by pointer.
+func isEntryForRule(entry discovery.Entry, rule parser.Rule) bool {
+	if rule.AlertingRule != nil {
+		return entry.Rule.AlertingRule == rule.AlertingRule
+	}
+	if rule.RecordingRule != nil {
+		return entry.Rule.RecordingRule == rule.RecordingRule
+	}
+	return false
+}
+
+// normalisedExpr returns the query of given rule formatted by the PromQL
+// parser, so queries that only differ in formatting are equal.
+func normalisedExpr(rule parser.Rule) string {
+	expr := rule.Expr()
+	if expr.Query == nil || expr.Query.Node == nil {
+		return expr.Value.Value
+	}
+	return expr.Query.Node.String()
+}
+
+// shareServers returns true if both lists have at least one common server.
+func shareServers(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
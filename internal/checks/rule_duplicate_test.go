@@ -0,0 +1,235 @@
+package checks_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func newRuleDuplicateCheck(_ string) checks.RuleChecker {
+	return checks.NewRuleDuplicateCheck(nil)
+}
+
+// newRuleDuplicateCheckWithServers returns a check where files named
+// {server}.yml are deployed to that server and the checked rule (with empty
+// path) is deployed to servers a and b.
+func newRuleDuplicateCheckWithServers(_ string) checks.RuleChecker {
+	return checks.NewRuleDuplicateCheck(func(path string, _ parser.Rule) []string {
+		if path == "" {
+			return []string{"a", "b"}
+		}
+		return []string{strings.TrimSuffix(path, ".yml")}
+	})
+}
+
+func entriesWithPath(path string, entries []discovery.Entry) []discovery.Entry {
+	for i := range entries {
+		entries[i].Path = path
+	}
+	return entries
+}
+
+func TestRuleDuplicateCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with different names",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- record: bar\n  expr: sum(foo)\n")),
+			problems:    noProblems,
+		},
+		{
+			description: "ignores rules with different labels",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    job: foo\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n  labels:\n    job: bar\n")),
+			problems:    noProblems,
+		},
+		{
+			description: "ignores rules of different type",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- alert: foo\n  expr: sum(foo) > 0\n")),
+			problems:    noProblems,
+		},
+		{
+			description: "ignores entries with errors",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n  foo: bar\n")),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated recording rule",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    job: foo\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- record: bar\n  expr: sum(bar)\n- record: foo\n  expr: sum(bar)\n  labels:\n    job: foo\n")),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1, 2, 3, 4},
+						Reporter: checks.RuleDuplicateCheckName,
+						Text:     `recording rule "foo" with identical labels but a different query is also defined in other.yml on line 3, both rules will produce the same time series`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated alerting rule",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- alert: foo\n  expr: up{job=\"foo\"} == 0\n  for: 5m\n")),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1, 2},
+						Reporter: checks.RuleDuplicateCheckName,
+						Text:     `alerting rule "foo" with identical labels but a different query is also defined in other.yml on line 1, both rules will produce alerts with the same labels`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated in multiple files",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleDuplicateCheck,
+			entries: append(
+				entriesWithPath("a.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+				entriesWithPath("b.yml", mustParseContent("\n\n- record: foo\n  expr: sum(foo)\n"))...,
+			),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1, 2},
+						Reporter: checks.RuleDuplicateCheckName,
+						Text:     `recording rule "foo" with identical labels and query is also defined in a.yml on line 1, both rules will produce the same time series`,
+						Severity: checks.Bug,
+					},
+					{
+						Fragment: "foo",
+						Lines:    []int{1, 2},
+						Reporter: checks.RuleDuplicateCheckName,
+						Text:     `recording rule "foo" with identical labels and query is also defined in b.yml on line 3, both rules will produce the same time series`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated query with different formatting",
+			content:     "- alert: foo\n  expr: up{job=\"foo\"} == 0\n",
+			checker:     newRuleDuplicateCheck,
+			entries:     entriesWithPath("other.yml", mustParseContent("- alert: foo\n  expr: up{job='foo'}  ==  0\n")),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1, 2},
+						Reporter: checks.RuleDuplicateCheckName,
+						Text:     `alerting rule "foo" with identical labels and query is also defined in other.yml on line 1, both rules will produce alerts with the same labels`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "ignores rules deployed to different servers",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleDuplicateCheckWithServers,
+			entries:     entriesWithPath("c.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated on a common server",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleDuplicateCheckWithServers,
+			entries: append(
+				entriesWithPath("b.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+				entriesWithPath("c.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n"))...,
+			),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1, 2},
+						Reporter: checks.RuleDuplicateCheckName,
+						Text:     `recording rule "foo" with identical labels and query is also defined in b.yml on line 1, both rules will produce the same time series`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+	}
+	runTests(t, testCases)
+}
+
+func TestRuleDuplicateCheckSameFile(t *testing.T) {
+	entries := mustParseContent(`
+groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(foo)
+  - record: foo
+    expr: sum(foo)
+- name: bar
+  rules:
+  - record: foo
+    expr: sum(foo)
+`)
+	require.Len(t, entries, 3)
+
+	c := checks.NewRuleDuplicateCheck(nil)
+
+	// rules from the same group are reported by rule/group, so only
+	// the rule from the other group is a duplicate
+	problems := c.Check(context.Background(), entries[0].Rule, entries)
+	require.Equal(t, []checks.Problem{
+		{
+			Fragment: "foo",
+			Lines:    []int{5, 6},
+			Reporter: checks.RuleDuplicateCheckName,
+			Text:     `recording rule "foo" with identical labels and query is also defined in fake.yml on line 11, both rules will produce the same time series`,
+			Severity: checks.Bug,
+		},
+	}, problems)
+
+	problems = c.Check(context.Background(), entries[2].Rule, entries)
+	require.Len(t, problems, 2)
+}
+
+func TestRuleDuplicateCheckSameFileWithoutGroups(t *testing.T) {
+	entries := mustParseContent(`
+- record: foo
+  expr: sum(foo)
+- record: foo
+  expr: sum(foo)
+`)
+	require.Len(t, entries, 2)
+
+	c := checks.NewRuleDuplicateCheck(nil)
+	require.Empty(t, c.Check(context.Background(), entries[0].Rule, entries))
+
+	other := entriesWithPath("other.yml", mustParseContent("- record: foo\n  expr: sum(foo)\n"))
+	problems := c.Check(context.Background(), entries[0].Rule, append(entries, other...))
+	require.Equal(t, []checks.Problem{
+		{
+			Fragment: "foo",
+			Lines:    []int{2, 3},
+			Reporter: checks.RuleDuplicateCheckName,
+			Text:     `recording rule "foo" with identical labels and query is also defined in other.yml on line 1, both rules will produce the same time series`,
+			Severity: checks.Bug,
+		},
+	}, problems)
+}
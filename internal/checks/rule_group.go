@@ -0,0 +1,383 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	RuleGroupCheckName = "rule/group"
+
+	groupLimitLookBack = time.Hour * 24
+	groupLimitStep     = time.Minute
+)
+
+func NewRuleGroupCheck(prom *promapi.FailoverGroup) RuleGroupCheck {
+	return RuleGroupCheck{prom: prom}
+}
+
+type RuleGroupCheck struct {
+	prom *promapi.FailoverGroup
+}
+
+func (c RuleGroupCheck) String() string {
+	if c.prom == nil {
+		return RuleGroupCheckName
+	}
+	return fmt.Sprintf("%s(%s)", RuleGroupCheckName, c.prom.Name())
+}
+
+func (c RuleGroupCheck) Reporter() string {
+	return RuleGroupCheckName
+}
+
+func (c RuleGroupCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.Group == nil {
+		return
+	}
+
+	path, siblings := groupSiblings(rule, entries)
+	isFirst := len(siblings) == 0 || isSameRule(siblings[0].Rule, rule)
+
+	if c.prom == nil {
+		if isFirst {
+			problems = append(problems, c.checkGroupName(rule.Group, rule.Document, path, entries)...)
+			problems = append(problems, c.checkGroupFields(rule.Group)...)
+		}
+		problems = append(problems, c.checkRuleNames(rule, siblings)...)
+		return
+	}
+
+	if isFirst {
+		problems = append(problems, c.checkInterval(ctx, rule.Group)...)
+	}
+	problems = append(problems, c.checkLimit(ctx, rule)...)
+	return
+}
+
+func (c RuleGroupCheck) checkGroupName(group *parser.RuleGroup, document int, path string, entries []discovery.Entry) (problems []Problem) {
+	for _, entry := range entries {
+		// every YAML document is a separate set of groups
+		if entry.Path != path || entry.Rule.Document != document || entry.Rule.Group == nil {
+			continue
+		}
+		other := entry.Rule.Group
+		if other.Name.Value.Value != group.Name.Value.Value {
+			continue
+		}
+		if other.Name.Value.Position.FirstLine() >= group.Name.Value.Position.FirstLine() {
+			continue
+		}
+		problems = append(problems, Problem{
+			Fragment: fmt.Sprintf("%s: %s", group.Name.Key.Value, group.Name.Value.Value),
+			Lines:    group.Name.Lines(),
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("duplicated group name %q, first defined on line %d",
+				group.Name.Value.Value, other.Name.Value.Position.FirstLine()),
+			Severity: Bug,
+		})
+		return
+	}
+	return
+}
+
+func (c RuleGroupCheck) checkGroupFields(group *parser.RuleGroup) (problems []Problem) {
+	for _, field := range []*parser.YamlKeyValue{group.Interval, group.EvaluationDelay, group.QueryOffset} {
+		if field == nil {
+			continue
+		}
+		if _, err := model.ParseDuration(field.Value.Value); err != nil {
+			problems = append(problems, Problem{
+				Fragment: field.Value.Value,
+				Lines:    field.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("invalid group %s: %s", field.Key.Value, err),
+				Severity: Bug,
+			})
+		}
+	}
+
+	if group.PartialResponseStrategy != nil {
+		switch group.PartialResponseStrategy.Value.Value {
+		case "warn", "abort":
+		default:
+			problems = append(problems, Problem{
+				Fragment: group.PartialResponseStrategy.Value.Value,
+				Lines:    group.PartialResponseStrategy.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("invalid group partial_response_strategy %q, must be one of: warn, abort", group.PartialResponseStrategy.Value.Value),
+				Severity: Bug,
+			})
+		}
+	}
+
+	if group.SourceTenants != nil {
+		for _, tenant := range group.SourceTenants.Items {
+			if tenant.Value == "" {
+				problems = append(problems, Problem{
+					Fragment: group.SourceTenants.Key.Value,
+					Lines:    group.SourceTenants.Lines(),
+					Reporter: c.Reporter(),
+					Text:     "group source_tenants cannot contain empty values",
+					Severity: Bug,
+				})
+				break
+			}
+		}
+	}
+
+	if group.Limit != nil {
+		if limit, err := strconv.Atoi(group.Limit.Value.Value); err != nil || limit < 0 {
+			problems = append(problems, Problem{
+				Fragment: group.Limit.Value.Value,
+				Lines:    group.Limit.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("invalid group limit %q, must be a non-negative integer", group.Limit.Value.Value),
+				Severity: Bug,
+			})
+		}
+	}
+
+	return
+}
+
+func (c RuleGroupCheck) checkRuleNames(rule parser.Rule, siblings []discovery.Entry) (problems []Problem) {
+	name, kind := ruleNameAndKind(rule)
+	if name == "" {
+		return
+	}
+
+	for _, entry := range siblings {
+		if isSameRule(entry.Rule, rule) {
+			// only rules defined before this one are compared, so we
+			// report each duplicate once
+			return
+		}
+		if n, k := ruleNameAndKind(entry.Rule); n != name || k != kind {
+			continue
+		}
+		// rules with the same name but different labels are commonly used
+		// to alert on different thresholds, so only report exact copies
+		if !sameLabels(ruleLabels(entry.Rule), ruleLabels(rule)) {
+			continue
+		}
+
+		problems = append(problems, Problem{
+			Fragment: name,
+			Lines:    rule.Lines(),
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("%s rule %q is already defined on line %d in the same group with identical labels",
+				kind, name, entry.Rule.Lines()[0]),
+			Severity: Bug,
+		})
+		return
+	}
+
+	return
+}
+
+func (c RuleGroupCheck) checkInterval(ctx context.Context, group *parser.RuleGroup) (problems []Problem) {
+	if group.Interval == nil {
+		return
+	}
+
+	interval, err := model.ParseDuration(group.Interval.Value.Value)
+	if err != nil || interval == 0 {
+		return
+	}
+
+	cfg, err := c.prom.Config(ctx)
+	if err != nil {
+		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+		problems = append(problems, Problem{
+			Fragment: group.Interval.Value.Value,
+			Lines:    group.Interval.Lines(),
+			Reporter: c.Reporter(),
+			Text:     text,
+			Severity: severity,
+		})
+		return
+	}
+
+	if time.Duration(interval) < cfg.Config.Global.ScrapeInterval {
+		problems = append(problems, Problem{
+			Fragment: group.Interval.Value.Value,
+			Lines:    group.Interval.Lines(),
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("group interval %s is lower than %s scrape_interval %s, rules will be evaluated more often than new samples are collected",
+				output.HumanizeDuration(time.Duration(interval)),
+				promText(c.prom.Name(), cfg.URI),
+				output.HumanizeDuration(cfg.Config.Global.ScrapeInterval)),
+			Severity: Warning,
+		})
+	}
+
+	return
+}
+
+func (c RuleGroupCheck) checkLimit(ctx context.Context, rule parser.Rule) (problems []Problem) {
+	if rule.AlertingRule == nil || rule.AlertingRule.Expr.SyntaxError != nil || rule.Group.Limit == nil {
+		return
+	}
+
+	limit, err := strconv.Atoi(rule.Group.Limit.Value.Value)
+	if err != nil || limit <= 0 {
+		return
+	}
+
+	qr, err := c.prom.RangeQuery(ctx, rule.AlertingRule.Expr.Value.Value, groupLimitLookBack, groupLimitStep)
+	if err != nil {
+		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+		problems = append(problems, Problem{
+			Fragment: rule.AlertingRule.Expr.Value.Value,
+			Lines:    rule.AlertingRule.Expr.Lines(),
+			Reporter: c.Reporter(),
+			Text:     text,
+			Severity: severity,
+		})
+		return
+	}
+
+	peak := peakAlerts(firingAlerts(rule.AlertingRule, qr.Samples, groupLimitStep))
+	if peak > limit {
+		lines := mergeLines(rule.AlertingRule.Expr.Lines(), rule.Group.Limit.Lines())
+		if rule.AlertingRule.For != nil {
+			lines = mergeLines(lines, rule.AlertingRule.For.Lines())
+		}
+		if rule.AlertingRule.KeepFiringFor != nil {
+			lines = mergeLines(lines, rule.AlertingRule.KeepFiringFor.Lines())
+		}
+		problems = append(problems, Problem{
+			Fragment: rule.AlertingRule.Expr.Value.Value,
+			Lines:    lines,
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("%s would produce up to %d alert(s) at once in the last %s but group limit is %d, all alerts from this rule would be dropped when the limit is exceeded%s",
+				promText(c.prom.Name(), qr.URI), peak, output.HumanizeDuration(qr.End.Sub(qr.Start)), limit, incompleteText(qr.Missing())),
+			Severity: Bug,
+		})
+	}
+
+	return
+}
+
+// peakAlerts returns the highest number of alerts firing at the same time.
+func peakAlerts(alerts []alertWindow) (peak int) {
+	type event struct {
+		ts    time.Time
+		delta int
+	}
+	events := make([]event, 0, len(alerts)*2)
+	for _, alert := range alerts {
+		events = append(events, event{ts: alert.start, delta: 1}, event{ts: alert.end, delta: -1})
+	}
+	// alerts are firing at both ends of their time range, so on equal
+	// timestamps we need to count new alerts before resolved ones
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].ts.Equal(events[j].ts) {
+			return events[i].delta > events[j].delta
+		}
+		return events[i].ts.Before(events[j].ts)
+	})
+
+	var active int
+	for _, e := range events {
+		active += e.delta
+		if active > peak {
+			peak = active
+		}
+	}
+	return peak
+}
+
+// groupSiblings returns the path of the file given rule was read from and all
+// entries defined in the same group, ordered as they appear in that file.
+func groupSiblings(rule parser.Rule, entries []discovery.Entry) (path string, siblings []discovery.Entry) {
+	for _, entry := range entries {
+		if isSameRule(entry.Rule, rule) && entry.Rule.Document == rule.Document && sameGroup(entry.Rule.Group, rule.Group) {
+			path = entry.Path
+			break
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Path == path && entry.Rule.Document == rule.Document && sameGroup(entry.Rule.Group, rule.Group) {
+			siblings = append(siblings, entry)
+		}
+	}
+	return path, siblings
+}
+
+func sameGroup(a, b *parser.RuleGroup) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Name.Value.Value == b.Name.Value.Value &&
+		a.Name.Value.Position.FirstLine() == b.Name.Value.Position.FirstLine()
+}
+
+func isSameRule(a, b parser.Rule) bool {
+	an, ak := ruleNameAndKind(a)
+	bn, bk := ruleNameAndKind(b)
+	if an != bn || ak != bk {
+		return false
+	}
+	al, bl := a.Lines(), b.Lines()
+	if len(al) != len(bl) {
+		return false
+	}
+	for i := range al {
+		if al[i] != bl[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func ruleNameAndKind(rule parser.Rule) (string, string) {
+	if rule.AlertingRule != nil {
+		return rule.AlertingRule.Alert.Value.Value, "alerting"
+	}
+	if rule.RecordingRule != nil {
+		return rule.RecordingRule.Record.Value.Value, "recording"
+	}
+	return "", ""
+}
+
+func ruleLabels(rule parser.Rule) (labels map[string]string) {
+	labels = map[string]string{}
+	var ym *parser.YamlMap
+	if rule.AlertingRule != nil {
+		ym = rule.AlertingRule.Labels
+	}
+	if rule.RecordingRule != nil {
+		ym = rule.RecordingRule.Labels
+	}
+	if ym != nil {
+		for _, item := range ym.Items {
+			labels[item.Key.Value] = item.Value.Value
+		}
+	}
+	return labels
+}
+
+func sameLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,472 @@
+package checks_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func newRuleGroupCheck(_ string) checks.RuleChecker {
+	return checks.NewRuleGroupCheck(nil)
+}
+
+func newRuleGroupOnlineCheck(uri string) checks.RuleChecker {
+	return checks.NewRuleGroupCheck(simpleProm("prom", uri, time.Second, true))
+}
+
+func groupIntervalText(name, uri, interval, scrape string) string {
+	return fmt.Sprintf("group interval %s is lower than prometheus %q at %s scrape_interval %s, rules will be evaluated more often than new samples are collected", interval, name, uri, scrape)
+}
+
+func groupLimitText(name, uri string, peak, limit int) string {
+	return fmt.Sprintf("prometheus %q at %s would produce up to %d alert(s) at once in the last 1d but group limit is %d, all alerts from this rule would be dropped when the limit is exceeded", name, uri, peak, limit)
+}
+
+func TestRuleGroupCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules without a group",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n- record: foo\n  expr: sum(foo)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "valid group",
+			content:     "groups:\n- name: foo\n  interval: 1m\n  limit: 10\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  interval: 1m\n  limit: 10\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated group name",
+			content:     "groups:\n\n\n\n\n- name: foo\n  rules:\n  - record: bar\n    expr: sum(bar)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n- name: foo\n  rules:\n  - record: bar\n    expr: sum(bar)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "name: foo",
+						Lines:    []int{6},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `duplicated group name "foo", first defined on line 2`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "same group name in different documents",
+			content:     "groups: []\n---\ngroups:\n\n\n\n\n- name: foo\n  rules:\n  - record: bar\n    expr: sum(bar)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n---\ngroups:\n- name: foo\n  rules:\n  - record: bar\n    expr: sum(bar)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "first group with duplicated name",
+			content:     "groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n- name: foo\n  rules:\n  - record: bar\n    expr: sum(bar)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "invalid interval",
+			content:     "groups:\n- name: foo\n  interval: 1x\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  interval: 1x\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "1x",
+						Lines:    []int{3},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `invalid group interval: not a valid duration string: "1x"`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "invalid evaluation_delay",
+			content:     "namespace: foo\ngroups:\n- name: foo\n  evaluation_delay: abc\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("namespace: foo\ngroups:\n- name: foo\n  evaluation_delay: abc\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "abc",
+						Lines:    []int{4},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `invalid group evaluation_delay: not a valid duration string: "abc"`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "invalid partial_response_strategy",
+			content:     "groups:\n- name: foo\n  partial_response_strategy: ignore\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  partial_response_strategy: ignore\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "ignore",
+						Lines:    []int{3},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `invalid group partial_response_strategy "ignore", must be one of: warn, abort`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "empty source tenant",
+			content:     "namespace: foo\ngroups:\n- name: foo\n  source_tenants:\n  - a\n  - \"\"\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("namespace: foo\ngroups:\n- name: foo\n  source_tenants:\n  - a\n  - \"\"\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "source_tenants",
+						Lines:    []int{4, 5, 6},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     "group source_tenants cannot contain empty values",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "valid ruler group",
+			content:     "namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  evaluation_delay: 1m\n  query_offset: 30s\n  partial_response_strategy: warn\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  evaluation_delay: 1m\n  query_offset: 30s\n  partial_response_strategy: warn\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "invalid limit",
+			content:     "groups:\n- name: foo\n  limit: -1\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: -1\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "-1",
+						Lines:    []int{3},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `invalid group limit "-1", must be a non-negative integer`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated recording rule with identical labels",
+			content:     "groups:\n- name: foo\n  rules:\n\n\n  - record: foo\n    expr: sum(bar)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n  - record: foo\n    expr: sum(bar)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{6, 7},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `recording rule "foo" is already defined on line 4 in the same group with identical labels`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated alerting rule with different labels",
+			content:     "groups:\n- name: foo\n  rules:\n\n\n\n\n  - alert: foo\n    expr: up == 0\n    labels:\n      severity: critical\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: up == 0\n    labels:\n      severity: warning\n  - alert: foo\n    expr: up == 0\n    labels:\n      severity: critical\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated alerting rule with identical labels",
+			content:     "groups:\n- name: foo\n  rules:\n\n\n\n\n  - alert: foo\n    expr: up == 0\n    labels:\n      severity: critical\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: up == 1\n    labels:\n      severity: critical\n  - alert: foo\n    expr: up == 0\n    labels:\n      severity: critical\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{8, 9, 10, 11},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     `alerting rule "foo" is already defined on line 4 in the same group with identical labels`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "same name in different groups",
+			content:     "groups:\n\n\n\n\n- name: bar\n  rules:\n  - record: foo\n    expr: sum(bar)\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n- name: bar\n  rules:\n  - record: foo\n    expr: sum(bar)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "alerting and recording rule with the same name",
+			content:     "groups:\n- name: foo\n  rules:\n\n\n  - alert: foo\n    expr: up == 0\n",
+			checker:     newRuleGroupCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n  - alert: foo\n    expr: up == 0\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "interval higher than scrape_interval",
+			content:     "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  interval: 2m\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "interval lower than scrape_interval",
+			content:     "groups:\n- name: foo\n  interval: 15s\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  interval: 15s\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "15s",
+						Lines:    []int{3},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     groupIntervalText("prom", uri, "15s", "1m"),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "interval check with connection refused",
+			content:     "groups:\n- name: foo\n  interval: 15s\n  rules:\n  - record: foo\n    expr: sum(foo)\n",
+			checker: func(_ string) checks.RuleChecker {
+				return checks.NewRuleGroupCheck(simpleProm("prom", "http://127.0.0.1:1111", time.Second, true))
+			},
+			entries: mustParseContent("groups:\n- name: foo\n  interval: 15s\n  rules:\n  - record: foo\n    expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "15s",
+						Lines:    []int{3},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     checkErrorUnableToRun(checks.RuleGroupCheckName, "prom", "http://127.0.0.1:1111", `failed to query Prometheus config: Get "http://127.0.0.1:1111/api/v1/status/config": dial tcp 127.0.0.1:1111: connect: connection refused`),
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "alerts below group limit",
+			content:     "groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n"),
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "up == 0"},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(
+								map[string]string{"instance": "a"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "b"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "alerts above group limit",
+			content:     "groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "up == 0",
+						Lines:    []int{3, 6},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     groupLimitText("prom", uri, 3, 2),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "up == 0"},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(
+								map[string]string{"instance": "a"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "b"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "c"},
+								time.Now().Add(time.Hour*-2),
+								time.Now().Add(time.Hour*-1),
+								time.Minute,
+							),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "alerts above group limit only while pending",
+			content:     "groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n    for: 10m\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n    for: 10m\n"),
+			problems:    noProblems,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "up == 0"},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(
+								map[string]string{"instance": "a"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "b"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "c"},
+								time.Now().Add(time.Hour*-2),
+								time.Now().Add(time.Hour*-2).Add(time.Minute*5),
+								time.Minute,
+							),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "alerts above group limit with keep_firing_for",
+			content:     "groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n    keep_firing_for: 10m\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n    keep_firing_for: 10m\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "up == 0",
+						Lines:    []int{3, 6, 7},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     groupLimitText("prom", uri, 3, 2),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "up == 0"},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(
+								map[string]string{"instance": "a"},
+								time.Now().Add(time.Hour*-24),
+								time.Now(),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "b"},
+								time.Now().Add(time.Hour*-3),
+								time.Now().Add(time.Hour*-2),
+								time.Minute,
+							),
+							generateSampleStream(
+								map[string]string{"instance": "c"},
+								time.Now().Add(time.Hour*-2).Add(time.Minute*5),
+								time.Now().Add(time.Hour*-1),
+								time.Minute,
+							),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "recording rules are not checked against group limit",
+			content:     "groups:\n- name: foo\n  limit: 1\n  rules:\n  - record: foo\n    expr: up == 0\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: 1\n  rules:\n  - record: foo\n    expr: up == 0\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "group limit query error",
+			content:     "groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n",
+			checker:     newRuleGroupOnlineCheck,
+			entries:     mustParseContent("groups:\n- name: foo\n  limit: 2\n  rules:\n  - alert: foo\n    expr: up == 0\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "up == 0",
+						Lines:    []int{6},
+						Reporter: checks.RuleGroupCheckName,
+						Text:     checkErrorBadData("prom", uri, "bad_data: bad input data"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireRangeQueryPath},
+					resp:  respondWithBadData(),
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
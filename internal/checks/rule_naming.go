@@ -0,0 +1,175 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/utils"
+)
+
+const (
+	NamingCheckName = "rule/naming"
+)
+
+func NewNamingCheck(level, operations bool, severity Severity) NamingCheck {
+	return NamingCheck{level: level, operations: operations, severity: severity}
+}
+
+type NamingCheck struct {
+	level      bool
+	operations bool
+	severity   Severity
+}
+
+func (c NamingCheck) String() string {
+	return NamingCheckName
+}
+
+func (c NamingCheck) Reporter() string {
+	return NamingCheckName
+}
+
+func (c NamingCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.RecordingRule == nil || rule.RecordingRule.Expr.SyntaxError != nil {
+		return nil
+	}
+
+	name := rule.RecordingRule.Record.Value.Value
+	fragment := fmt.Sprintf("%s: %s", rule.RecordingRule.Record.Key.Value, name)
+	parts := strings.Split(name, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		problems = append(problems, Problem{
+			Fragment: fragment,
+			Lines:    rule.RecordingRule.Record.Lines(),
+			Reporter: c.Reporter(),
+			Text:     fmt.Sprintf("recording rule name %q doesn't follow the level:metric:operations naming convention", name),
+			Severity: c.severity,
+		})
+		return problems
+	}
+
+	expr := rule.RecordingRule.Expr
+
+	if c.level {
+		for _, text := range checkLevel(name, parts[0], utils.OutputLabels(expr.Query, recordingRules(entries))) {
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    rule.RecordingRule.Record.Lines(),
+				Reporter: c.Reporter(),
+				Text:     text,
+				Severity: c.severity,
+			})
+		}
+	}
+
+	if c.operations {
+		for _, op := range queryOperations(expr.Query) {
+			if hasOperation(parts[2], op.name) {
+				continue
+			}
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    rule.RecordingRule.Record.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("operations part of recording rule name %q should include %q because the query uses %s", name, op.name, op.source),
+				Severity: c.severity,
+			})
+		}
+	}
+
+	return problems
+}
+
+// checkLevel validates that the level part of the name lists labels
+// preserved by the query.
+func checkLevel(name, level string, ls utils.LabelSet) (problems []string) {
+	if ls.Fixed {
+		if len(ls.Possible) > 0 && !levelMatches(level, ls.Possible) {
+			problems = append(problems, fmt.Sprintf("level part of recording rule name %q should list all labels preserved by the query: %s",
+				name, strings.Join(ls.Possible, ", ")))
+		}
+		return problems
+	}
+
+	for _, label := range ls.Excluded {
+		if hasOperation(level, label) {
+			problems = append(problems, fmt.Sprintf("level part of recording rule name %q includes %q label which is removed by the query", name, label))
+		}
+	}
+	return problems
+}
+
+// levelMatches returns true if level is made of all labels joined with "_"
+// in any order.
+func levelMatches(level string, labels []string) bool {
+	for i, label := range labels {
+		rest := append(append([]string{}, labels[:i]...), labels[i+1:]...)
+		if level == label && len(rest) == 0 {
+			return true
+		}
+		if strings.HasPrefix(level, label+"_") && levelMatches(level[len(label)+1:], rest) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOperation returns true if s contains name delimited by "_" or string boundaries.
+func hasOperation(s, name string) bool {
+	return strings.Contains("_"+s+"_", "_"+name+"_")
+}
+
+type operation struct {
+	name   string
+	source string
+}
+
+// queryOperations returns all operations that should be listed in the name
+// of a recording rule using given query.
+// sum() is implied by the level part of the name, so it doesn't need to be listed.
+func queryOperations(node *parser.PromQLNode) (ops []operation) {
+	switch n := node.Node.(type) {
+	case *promParser.Call:
+		switch n.Func.Name {
+		case "rate", "irate", "increase", "delta", "idelta", "deriv":
+			for _, arg := range n.Args {
+				if m, ok := arg.(*promParser.MatrixSelector); ok {
+					ops = appendOperation(ops, operation{
+						name:   n.Func.Name + model.Duration(m.Range).String(),
+						source: n.Func.Name + "()",
+					})
+				}
+			}
+		}
+	case *promParser.AggregateExpr:
+		if n.Op != promParser.SUM {
+			ops = appendOperation(ops, operation{
+				name:   n.Op.String(),
+				source: n.Op.String() + "()",
+			})
+		}
+	}
+
+	for _, child := range node.Children {
+		for _, op := range queryOperations(child) {
+			ops = appendOperation(ops, op)
+		}
+	}
+
+	return ops
+}
+
+func appendOperation(ops []operation, op operation) []operation {
+	for _, o := range ops {
+		if o.name == op.name {
+			return ops
+		}
+	}
+	return append(ops, op)
+}
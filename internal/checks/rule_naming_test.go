@@ -0,0 +1,206 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func newNamingCheck(_ string) checks.RuleChecker {
+	return checks.NewNamingCheck(true, true, checks.Warning)
+}
+
+func TestNamingCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "ignores alerting rules",
+			content:     "- alert: foo\n  expr: rate(foo[5m]) > 0\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "name without colons",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newNamingCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: foo",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `recording rule name "foo" doesn't follow the level:metric:operations naming convention`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "name with empty level",
+			content:     "- record: :foo:sum\n  expr: sum(foo)\n",
+			checker: func(_ string) checks.RuleChecker {
+				return checks.NewNamingCheck(false, false, checks.Bug)
+			},
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: :foo:sum",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `recording rule name ":foo:sum" doesn't follow the level:metric:operations naming convention`,
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "valid rate",
+			content:     "- record: instance_path:requests:rate5m\n  expr: rate(requests_total[5m])\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid sum by",
+			content:     "- record: job_instance:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(instance, job)\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid sum by, sum listed in operations",
+			content:     "- record: job:requests:sum_rate5m\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid sum without",
+			content:     "- record: path:requests:rate5m\n  expr: sum without(instance) (instance_path:requests:rate5m)\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "valid max",
+			content:     "- record: job:requests:max_rate5m\n  expr: max(rate(requests_total[5m])) by(job)\n",
+			checker:     newNamingCheck,
+			problems:    noProblems,
+		},
+		{
+			description: "level doesn't match by()",
+			content:     "- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(job, instance)\n",
+			checker:     newNamingCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:requests:rate5m",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `level part of recording rule name "job:requests:rate5m" should list all labels preserved by the query: instance, job`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "level mentions label removed by without()",
+			content:     "- record: instance:requests:rate5m\n  expr: sum(rate(requests_total[5m])) without(instance)\n",
+			checker:     newNamingCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: instance:requests:rate5m",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `level part of recording rule name "instance:requests:rate5m" includes "instance" label which is removed by the query`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "level resolved using recording rules",
+			content:     "- record: cluster:requests:rate5m\n  expr: job:requests:rate5m\n",
+			checker:     newNamingCheck,
+			entries:     mustParseContent("- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(job)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: cluster:requests:rate5m",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `level part of recording rule name "cluster:requests:rate5m" should list all labels preserved by the query: job`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "level checks disabled",
+			content:     "- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(job, instance)\n",
+			checker: func(_ string) checks.RuleChecker {
+				return checks.NewNamingCheck(false, true, checks.Warning)
+			},
+			problems: noProblems,
+		},
+		{
+			description: "missing rate in operations",
+			content:     "- record: job:requests:sum\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker:     newNamingCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:requests:sum",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `operations part of recording rule name "job:requests:sum" should include "rate5m" because the query uses rate()`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "wrong rate duration in operations",
+			content:     "- record: job:requests:rate1m\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker:     newNamingCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:requests:rate1m",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `operations part of recording rule name "job:requests:rate1m" should include "rate5m" because the query uses rate()`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "missing aggregation in operations",
+			content:     "- record: job:requests:increase1h\n  expr: avg(increase(requests_total[1h])) by(job)\n",
+			checker:     newNamingCheck,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:requests:increase1h",
+						Lines:    []int{1},
+						Reporter: checks.NamingCheckName,
+						Text:     `operations part of recording rule name "job:requests:increase1h" should include "avg" because the query uses avg()`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "operations checks disabled",
+			content:     "- record: job:requests:sum\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker: func(_ string) checks.RuleChecker {
+				return checks.NewNamingCheck(true, false, checks.Warning)
+			},
+			problems: noProblems,
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,290 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	RuleUnusedCheckName = "rule/unused"
+)
+
+func NewRuleUnusedCheck(prom *promapi.FailoverGroup, sources []string, severity Severity) RuleUnusedCheck {
+	return RuleUnusedCheck{prom: prom, sources: sources, severity: severity}
+}
+
+type RuleUnusedCheck struct {
+	prom     *promapi.FailoverGroup
+	sources  []string
+	severity Severity
+}
+
+func (c RuleUnusedCheck) String() string {
+	if c.prom == nil {
+		return RuleUnusedCheckName
+	}
+	return fmt.Sprintf("%s(%s)", RuleUnusedCheckName, c.prom.Name())
+}
+
+func (c RuleUnusedCheck) Reporter() string {
+	return RuleUnusedCheckName
+}
+
+func (c RuleUnusedCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.RecordingRule == nil || rule.RecordingRule.Expr.SyntaxError != nil {
+		return nil
+	}
+
+	g := graph.FromContext(ctx, entries)
+	node := g.Node(rule)
+	if node == nil {
+		return nil
+	}
+	for _, dep := range g.Dependents(node) {
+		if dep != node {
+			return nil
+		}
+	}
+
+	name := node.Name()
+	if isUsedByDashboards(name, entries) {
+		return nil
+	}
+
+	fragment := fmt.Sprintf("%s: %s", rule.RecordingRule.Record.Key.Value, name)
+
+	for _, pattern := range c.sources {
+		found, err := sourcesReference(pattern, name)
+		if err != nil {
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    rule.RecordingRule.Record.Lines(),
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("failed to read PromQL sources from %q: %s", pattern, err),
+				Severity: Bug,
+			})
+			return problems
+		}
+		if found {
+			return nil
+		}
+	}
+
+	text := fmt.Sprintf("recording rule %q isn't used by any other rule", name)
+	if len(c.sources) > 0 {
+		text = fmt.Sprintf("recording rule %q isn't used by any other rule or configured PromQL source", name)
+	}
+
+	if c.prom != nil {
+		q := fmt.Sprintf("count(%s)", name)
+		qr, err := c.prom.Query(ctx, q)
+		if err != nil {
+			text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    rule.RecordingRule.Record.Lines(),
+				Reporter: c.Reporter(),
+				Text:     text,
+				Severity: severity,
+			})
+			return problems
+		}
+		if len(qr.Series) == 0 {
+			text += fmt.Sprintf(", %s has no series for it", promText(c.prom.Name(), qr.URI))
+		} else {
+			text += fmt.Sprintf(", %s has %s series for it", promText(c.prom.Name(), qr.URI), qr.Series[0].Value)
+		}
+		text += ", but it might still be queried by something pint doesn't know about"
+	}
+
+	problems = append(problems, Problem{
+		Fragment: fragment,
+		Lines:    rule.RecordingRule.Record.Lines(),
+		Reporter: c.Reporter(),
+		Text:     text,
+		Severity: c.severity,
+	})
+	return problems
+}
+
+// isUsedByDashboards returns true if any query from Grafana dashboards
+// found in checked files is using given metric, either by name or with
+// a __name__ label matcher.
+func isUsedByDashboards(name string, entries []discovery.Entry) bool {
+	for _, entry := range entries {
+		if entry.Rule.DashboardQuery == nil || entry.Rule.DashboardQuery.Expr.Query == nil {
+			continue
+		}
+		var found bool
+		promParser.Inspect(entry.Rule.DashboardQuery.Expr.Query.Node, func(n promParser.Node, _ []promParser.Node) error {
+			vs, ok := n.(*promParser.VectorSelector)
+			if !ok {
+				return nil
+			}
+			if vs.Name == name {
+				found = true
+			}
+			for _, lm := range vs.LabelMatchers {
+				if lm.Name == labels.MetricName && lm.Matches(name) {
+					found = true
+				}
+			}
+			return nil
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+var metricNameRe = regexp.MustCompile(`[a-zA-Z_:][a-zA-Z0-9_:]*`)
+
+type sourceNames struct {
+	modTime time.Time
+	names   map[string]struct{}
+}
+
+var (
+	sourceCacheLock sync.Mutex
+	sourceCache     = map[string]sourceNames{}
+)
+
+// sourcesReference returns true if any file matching given glob pattern
+// contains a PromQL expression referencing given metric name.
+// Directories matching the pattern are searched recursively.
+func sourcesReference(pattern, name string) (bool, error) {
+	paths, err := sourceFiles(pattern)
+	if err != nil {
+		return false, err
+	}
+	for _, path := range paths {
+		names, err := readSourceNames(path)
+		if err != nil {
+			return false, err
+		}
+		if _, ok := names[name]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sourceFiles returns all regular files matching given glob pattern,
+// including files stored in matching directories.
+func sourceFiles(pattern string) (paths []string, err error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if info.Mode().IsRegular() {
+				paths = append(paths, match)
+			}
+			continue
+		}
+		err = filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// readSourceNames returns all metric names that might be used by PromQL
+// expressions stored in given file, results are cached until the file is modified.
+func readSourceNames(path string) (map[string]struct{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceCacheLock.Lock()
+	defer sourceCacheLock.Unlock()
+
+	if sn, ok := sourceCache[path]; ok && sn.modTime.Equal(info.ModTime()) {
+		return sn.names, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var exprs []string
+	if strings.HasSuffix(path, ".json") {
+		var doc interface{}
+		if err = json.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		exprs = jsonExprs(doc)
+	} else {
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				exprs = append(exprs, line)
+			}
+		}
+	}
+
+	// Expressions can use template variables, like Grafana $__rate_interval,
+	// so instead of parsing them we only look for anything that could be a metric name.
+	names := map[string]struct{}{}
+	for _, expr := range exprs {
+		for _, n := range metricNameRe.FindAllString(expr, -1) {
+			names[n] = struct{}{}
+		}
+	}
+
+	sourceCache[path] = sourceNames{modTime: info.ModTime(), names: names}
+	return names, nil
+}
+
+// jsonExprs returns values of all "expr" keys found in a JSON document,
+// which is where Grafana dashboards store PromQL queries.
+func jsonExprs(doc interface{}) (exprs []string) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if s, ok := val.(string); ok && key == "expr" {
+				exprs = append(exprs, s)
+				continue
+			}
+			exprs = append(exprs, jsonExprs(val)...)
+		}
+	case []interface{}:
+		for _, val := range v {
+			exprs = append(exprs, jsonExprs(val)...)
+		}
+	}
+	return exprs
+}
@@ -0,0 +1,334 @@
+package checks_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func mustParseDashboard(content string) parser.Rule {
+	rules, err := parser.NewParser().ParseDashboard([]byte(content))
+	if err != nil {
+		panic(err)
+	}
+	return rules[0]
+}
+
+func TestRuleUnusedCheck(t *testing.T) {
+	dir := t.TempDir()
+	dashboard := `{
+  "panels": [
+    {
+      "title": "Errors",
+      "targets": [{"expr": "sum(rate(job:errors:rate5m{job=~\"$job\"}[$__rate_interval]))", "refId": "A"}]
+    },
+    {
+      "type": "row",
+      "panels": [{"targets": [{"expr": "job:latency:p99 > 1"}]}]
+    }
+  ]
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboard.json"), []byte(dashboard), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "queries.txt"), []byte("# ad-hoc queries\njob:requests:rate5m / 2\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dashboards", "team"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards", "team", "api.json"), []byte(`{"panels": [{"targets": [{"expr": "job:requests:rate1m"}]}]}`), 0o644))
+
+	type testCaseT struct {
+		description string
+		entries     []discovery.Entry
+		sources     []string
+		prom        bool
+		problems    func(uri string) []checks.Problem
+		mocks       []*prometheusMock
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "alerting rules are ignored",
+			entries:     mustParseContent("- alert: foo\n  expr: up == 0\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "used by an alert",
+			entries:     mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n- alert: foo\n  expr: job:up:sum == 0\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "used by another recording rule",
+			entries:     mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n- record: up:sum\n  expr: sum(job:up:sum)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: up:sum",
+						Lines:    []int{3},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     `recording rule "up:sum" isn't used by any other rule`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "selector doesn't match static labels",
+			entries:     mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n  labels:\n    cluster: prod\n- alert: foo\n  expr: job:up:sum{cluster=\"dev\"} == 0\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:up:sum",
+						Lines:    []int{1},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     `recording rule "job:up:sum" isn't used by any other rule`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "self reference",
+			entries:     mustParseContent("- record: foo\n  expr: foo + 1\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: foo",
+						Lines:    []int{1},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     `recording rule "foo" isn't used by any other rule`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "used by sources",
+			entries: mustParseContent(`
+- record: job:errors:rate5m
+  expr: sum(rate(errors_total[5m])) by(job)
+- record: job:latency:p99
+  expr: histogram_quantile(0.99, sum(rate(latency_bucket[5m])) by(job, le))
+- record: job:requests:rate5m
+  expr: sum(rate(requests_total[5m])) by(job)
+- record: job:requests:rate1m
+  expr: sum(rate(requests_total[1m])) by(job)
+`),
+			sources: []string{filepath.Join(dir, "*.txt"), filepath.Join(dir, "dash*.json")},
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:requests:rate1m",
+						Lines:    []int{8},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     `recording rule "job:requests:rate1m" isn't used by any other rule or configured PromQL source`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "used by sources in a directory",
+			entries: mustParseContent(`
+- record: job:requests:rate1m
+  expr: sum(rate(requests_total[1m])) by(job)
+- record: job:requests:rate5m
+  expr: sum(rate(requests_total[5m])) by(job)
+`),
+			sources: []string{filepath.Join(dir, "dashboards")},
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:requests:rate5m",
+						Lines:    []int{4},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     `recording rule "job:requests:rate5m" isn't used by any other rule or configured PromQL source`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "used by sources matching directories",
+			entries:     mustParseContent("- record: job:requests:rate1m\n  expr: sum(rate(requests_total[1m])) by(job)\n"),
+			sources:     []string{filepath.Join(dir, "dash*")},
+			problems:    noProblems,
+		},
+		{
+			description: "used by dashboard queries",
+			entries: append(
+				mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n"),
+				discovery.Entry{Path: "dashboard.json", Rule: mustParseDashboard(`{"panels": [{"targets": [{"expr": "job:up:sum{job=\"$job\"}"}]}]}`)},
+			),
+			problems: noProblems,
+		},
+		{
+			description: "used by dashboard queries with __name__ matcher",
+			entries: append(
+				mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n"),
+				discovery.Entry{Path: "dashboard.json", Rule: mustParseDashboard(`{"panels": [{"targets": [{"expr": "{__name__=\"job:up:sum\", job=\"$job\"}"}]}]}`)},
+			),
+			problems: noProblems,
+		},
+		{
+			description: "used by dashboard queries with __name__ regexp",
+			entries: append(
+				mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n"),
+				discovery.Entry{Path: "dashboard.json", Rule: mustParseDashboard(`{"panels": [{"targets": [{"expr": "{__name__=~\"job:up:(sum|max)\"}"}]}]}`)},
+			),
+			problems: noProblems,
+		},
+		{
+			description: "not used by dashboard queries with __name__ regexp",
+			entries: append(
+				mustParseContent("- record: job:up:sum\n  expr: sum(up) by(job)\n"),
+				discovery.Entry{Path: "dashboard.json", Rule: mustParseDashboard(`{"panels": [{"targets": [{"expr": "{__name__=~\"job:up:(min|max)\"}"}]}]}`)},
+			),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: job:up:sum",
+						Lines:    []int{1},
+						Reporter: "rule/unused",
+						Text:     `recording rule "job:up:sum" isn't used by any other rule`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "broken source",
+			entries:     mustParseContent("- record: foo\n  expr: sum(bar)\n"),
+			sources:     []string{filepath.Join(dir, "*.json")},
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: foo",
+						Lines:    []int{1},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     fmt.Sprintf(`failed to read PromQL sources from %q: failed to parse %s: unexpected end of JSON input`, filepath.Join(dir, "*.json"), filepath.Join(dir, "broken.json")),
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "no series in Prometheus",
+			entries:     mustParseContent("- record: foo\n  expr: sum(bar)\n"),
+			prom:        true,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: foo",
+						Lines:    []int{1},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     fmt.Sprintf(`recording rule "foo" isn't used by any other rule, prometheus "prom" at %s has no series for it, but it might still be queried by something pint doesn't know about`, uri),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(foo)"},
+					},
+					resp: vectorResponse{samples: []*model.Sample{}},
+				},
+			},
+		},
+		{
+			description: "series present in Prometheus",
+			entries:     mustParseContent("- record: foo\n  expr: sum(bar)\n"),
+			prom:        true,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: foo",
+						Lines:    []int{1},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     fmt.Sprintf(`recording rule "foo" isn't used by any other rule, prometheus "prom" at %s has 1 series for it, but it might still be queried by something pint doesn't know about`, uri),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(foo)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{}),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "500 error from Prometheus",
+			entries:     mustParseContent("- record: foo\n  expr: sum(bar)\n"),
+			prom:        true,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "record: foo",
+						Lines:    []int{1},
+						Reporter: checks.RuleUnusedCheckName,
+						Text:     checkErrorUnableToRun(checks.RuleUnusedCheckName, "prom", uri, "server_error: server error: 500"),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var uri string
+			var prom *promapi.FailoverGroup
+			if tc.prom {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					for i := range tc.mocks {
+						if tc.mocks[i].maybeApply(w, r) {
+							return
+						}
+					}
+					t.Errorf("no matching response for %s request", r.URL.Path)
+					t.FailNow()
+				}))
+				defer srv.Close()
+				uri = srv.URL
+				prom = simpleProm("prom", uri, time.Second, true)
+			}
+
+			c := checks.NewRuleUnusedCheck(prom, tc.sources, checks.Warning)
+			var problems []checks.Problem
+			for _, entry := range tc.entries {
+				problems = append(problems, c.Check(context.Background(), entry.Rule, tc.entries)...)
+			}
+			require.Equal(t, tc.problems(uri), problems)
+
+			for _, mock := range tc.mocks {
+				require.True(t, mock.wasUsed(), "unused mock in %s: %s", tc.description, mock.conds)
+			}
+		})
+	}
+}
@@ -68,6 +68,9 @@ func newTemplateContext(rule parser.Rule) (c TemplateContext) {
 		if rule.AlertingRule.For != nil {
 			c.For = rule.AlertingRule.For.Value.Value
 		}
+		if rule.AlertingRule.KeepFiringFor != nil {
+			c.KeepFiringFor = rule.AlertingRule.KeepFiringFor.Value.Value
+		}
 		if rule.AlertingRule.Labels != nil {
 			for _, label := range rule.AlertingRule.Labels.Items {
 				c.Labels[label.Key.Value] = label.Value.Value
@@ -92,12 +95,13 @@ func newTemplateContext(rule parser.Rule) (c TemplateContext) {
 }
 
 type TemplateContext struct {
-	Alert       string
-	Record      string
-	Expr        string
-	For         string
-	Labels      map[string]string
-	Annotations map[string]string
+	Alert         string
+	Record        string
+	Expr          string
+	For           string
+	KeepFiringFor string
+	Labels        map[string]string
+	Annotations   map[string]string
 }
 
 func (tc TemplateContext) Aliases() string {
@@ -105,6 +109,7 @@ func (tc TemplateContext) Aliases() string {
 	vars.WriteString("{{ $alert := .Alert }}")
 	vars.WriteString("{{ $record := .Record }}")
 	vars.WriteString("{{ $for := .For }}")
+	vars.WriteString("{{ $keep_firing_for := .KeepFiringFor }}")
 	vars.WriteString("{{ $labels := .Labels }}")
 	vars.WriteString("{{ $annotations := .Annotations }}")
 	return vars.String()
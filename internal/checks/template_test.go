@@ -31,7 +31,7 @@ func TestTemplatedRegexpExpand(t *testing.T) {
 		{
 			input: "{{nil}}",
 			rule:  parser.Rule{},
-			err:   `template: regexp:1:125: executing "regexp" at <nil>: nil is not a command`,
+			err:   `template: regexp:1:165: executing "regexp" at <nil>: nil is not a command`,
 		},
 		{
 			input:  "",
@@ -53,6 +53,11 @@ func TestTemplatedRegexpExpand(t *testing.T) {
 			rule:   newMustRule("- alert: foo\n  expr: foo\n  for: 5m\n"),
 			output: "^for is 5m$",
 		},
+		{
+			input:  "keep_firing_for is {{ $keep_firing_for }}",
+			rule:   newMustRule("- alert: foo\n  expr: foo\n  keep_firing_for: 5m\n"),
+			output: "^keep_firing_for is 5m$",
+		},
 		{
 			input:  "record is {{ $record }}!",
 			rule:   newMustRule("- record: foo\n  expr: foo\n"),
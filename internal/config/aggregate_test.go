@@ -43,7 +43,7 @@ func TestAggregateSettings(t *testing.T) {
 				Name: "{{nil}}",
 				Keep: []string{"foo"},
 			},
-			err: errors.New(`template: regexp:1:125: executing "regexp" at <nil>: nil is not a command`),
+			err: errors.New(`template: regexp:1:165: executing "regexp" at <nil>: nil is not a command`),
 		},
 		{
 			conf: AggregateSettings{
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Cache struct {
+	Path     string `hcl:"path" json:"path"`
+	Query    string `hcl:"query,optional" json:"query,omitempty"`
+	Range    string `hcl:"range,optional" json:"range,omitempty"`
+	Config   string `hcl:"config,optional" json:"config,omitempty"`
+	Metadata string `hcl:"metadata,optional" json:"metadata,omitempty"`
+	Series   string `hcl:"series,optional" json:"series,omitempty"`
+}
+
+func (c Cache) validate() error {
+	if c.Path == "" {
+		return errors.New("cache path cannot be empty")
+	}
+
+	for _, ttl := range []struct {
+		name  string
+		value string
+	}{
+		{name: "query", value: c.Query},
+		{name: "range", value: c.Range},
+		{name: "config", value: c.Config},
+		{name: "metadata", value: c.Metadata},
+		{name: "series", value: c.Series},
+	} {
+		if ttl.value == "" {
+			continue
+		}
+		dur, err := parseDuration(ttl.value)
+		if err != nil {
+			return err
+		}
+		if dur <= 0 {
+			return fmt.Errorf("cache %s duration must be > 0", ttl.name)
+		}
+	}
+
+	return nil
+}
+
+// ttls returns the cache TTL for each Prometheus API endpoint.
+func (c Cache) ttls() map[string]time.Duration {
+	return map[string]time.Duration{
+		"/api/v1/query":         cacheTTL(c.Query, time.Minute*10),
+		"/api/v1/query_range":   cacheTTL(c.Range, time.Hour),
+		"/api/v1/status/config": cacheTTL(c.Config, time.Hour),
+		"/api/v1/metadata":      cacheTTL(c.Metadata, time.Hour),
+		"/api/v1/series":        cacheTTL(c.Series, time.Hour),
+		"/api/v1/labels":        cacheTTL(c.Series, time.Hour),
+		"/api/v1/label/values":  cacheTTL(c.Series, time.Hour),
+	}
+}
+
+func cacheTTL(s string, fallback time.Duration) time.Duration {
+	if s == "" {
+		return fallback
+	}
+	ttl, _ := parseDuration(s)
+	return ttl
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSettings(t *testing.T) {
+	type testCaseT struct {
+		conf Cache
+		err  error
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: Cache{Path: ".pint/cache"},
+		},
+		{
+			conf: Cache{
+				Path:     ".pint/cache",
+				Query:    "5m",
+				Range:    "1d",
+				Config:   "30m",
+				Metadata: "2h",
+			},
+		},
+		{
+			conf: Cache{},
+			err:  errors.New("cache path cannot be empty"),
+		},
+		{
+			conf: Cache{
+				Path:  ".pint/cache",
+				Range: "1x",
+			},
+			err: errors.New(`not a valid duration string: "1x"`),
+		},
+		{
+			conf: Cache{
+				Path:   ".pint/cache",
+				Config: "0s",
+			},
+			err: errors.New("cache config duration must be > 0"),
+		},
+		{
+			conf: Cache{
+				Path:     ".pint/cache",
+				Metadata: "-5m",
+			},
+			err: errors.New(`not a valid duration string: "-5m"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			assert := assert.New(t)
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				assert.Equal(err, tc.err)
+			} else {
+				assert.EqualError(err, tc.err.Error())
+			}
+		})
+	}
+}
+
+func TestCacheTTLs(t *testing.T) {
+	assert.Equal(t, map[string]time.Duration{
+		"/api/v1/query":         time.Minute * 10,
+		"/api/v1/query_range":   time.Hour,
+		"/api/v1/status/config": time.Hour,
+		"/api/v1/metadata":      time.Hour,
+		"/api/v1/series":        time.Hour,
+		"/api/v1/labels":        time.Hour,
+		"/api/v1/label/values":  time.Hour,
+	}, Cache{Path: "cache"}.ttls())
+
+	assert.Equal(t, map[string]time.Duration{
+		"/api/v1/query":         time.Minute * 5,
+		"/api/v1/query_range":   time.Hour * 24,
+		"/api/v1/status/config": time.Minute * 15,
+		"/api/v1/metadata":      time.Hour,
+		"/api/v1/series":        time.Minute * 30,
+		"/api/v1/labels":        time.Minute * 30,
+		"/api/v1/label/values":  time.Minute * 30,
+	}, Cache{Path: "cache", Query: "5m", Range: "1d", Config: "15m", Series: "30m"}.ttls())
+}
@@ -21,9 +21,11 @@ type Config struct {
 	Parser            *Parser            `hcl:"parser,block" json:"parser,omitempty"`
 	Repository        *Repository        `hcl:"repository,block" json:"repository,omitempty"`
 	Prometheus        []PrometheusConfig `hcl:"prometheus,block" json:"prometheus,omitempty"`
+	Cache             *Cache             `hcl:"cache,block" json:"cache,omitempty"`
 	Checks            *Checks            `hcl:"checks,block" json:"checks,omitempty"`
 	Rules             []Rule             `hcl:"rule,block" json:"rules,omitempty"`
 	prometheusServers []*promapi.FailoverGroup
+	offline           bool
 }
 
 func (cfg *Config) ClearCache() {
@@ -32,7 +34,16 @@ func (cfg *Config) ClearCache() {
 	}
 }
 
+func (cfg *Config) SetSession(s *promapi.Session) {
+	for _, prom := range cfg.prometheusServers {
+		prom.SetSession(s)
+	}
+}
+
 func (cfg *Config) DisableOnlineChecks() {
+	// checks that can run both with and without Prometheus will
+	// only run their offline part when no servers are selected
+	cfg.offline = true
 	for _, name := range checks.OnlineChecks {
 		var found bool
 		for _, n := range cfg.Checks.Disabled {
@@ -75,9 +86,26 @@ func (cfg Config) String() string {
 	return string(content)
 }
 
+// prometheusForRule returns names of all Prometheus servers given rule
+// would be deployed to, based on paths and tenants of each server.
+func (cfg *Config) prometheusForRule(path string, r parser.Rule) (names []string) {
+	for _, prom := range cfg.Prometheus {
+		if prom.isEnabledForPath(path) && prom.isEnabledForRule(r) {
+			names = append(names, prom.Name)
+		}
+	}
+	return names
+}
+
 func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.Rule) []checks.RuleChecker {
 	enabled := []checks.RuleChecker{}
 
+	// without any Prometheus servers all rules are compared with each other
+	var servers func(string, parser.Rule) []string
+	if len(cfg.Prometheus) > 0 {
+		servers = cfg.prometheusForRule
+	}
+
 	allChecks := []checkMeta{
 		{
 			name:  checks.SyntaxCheckName,
@@ -103,11 +131,35 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 			name:  checks.RegexpCheckName,
 			check: checks.NewRegexpCheck(),
 		},
+		{
+			name:  checks.HistogramCheckName,
+			check: checks.NewHistogramCheck(),
+		},
+		{
+			name:  checks.VectorMatchingCheckName,
+			check: checks.NewVectorMatchingCheck(nil),
+		},
+		{
+			name:  checks.LabelsConflictCheckName,
+			check: checks.NewLabelsConflictCheck(nil),
+		},
+		{
+			name:  checks.RuleGroupCheckName,
+			check: checks.NewRuleGroupCheck(nil),
+		},
+		{
+			name:  checks.RuleDuplicateCheckName,
+			check: checks.NewRuleDuplicateCheck(servers),
+		},
+		{
+			name:  checks.RuleDependencyCheckName,
+			check: checks.NewRuleDependencyCheck(),
+		},
 	}
 
 	proms := []*promapi.FailoverGroup{}
 	for _, prom := range cfg.Prometheus {
-		if !prom.isEnabledForPath(path) {
+		if cfg.offline || !prom.isEnabledForPath(path) || !prom.isEnabledForRule(r) {
 			continue
 		}
 		for _, p := range cfg.prometheusServers {
@@ -123,6 +175,10 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 			name:  checks.RateCheckName,
 			check: checks.NewRateCheck(p),
 		})
+		allChecks = append(allChecks, checkMeta{
+			name:  checks.CounterCheckName,
+			check: checks.NewCounterCheck(p),
+		})
 		allChecks = append(allChecks, checkMeta{
 			name:  checks.SeriesCheckName,
 			check: checks.NewSeriesCheck(p),
@@ -131,10 +187,22 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 			name:  checks.VectorMatchingCheckName,
 			check: checks.NewVectorMatchingCheck(p),
 		})
+		allChecks = append(allChecks, checkMeta{
+			name:  checks.LabelsConflictCheckName,
+			check: checks.NewLabelsConflictCheck(p),
+		})
+		allChecks = append(allChecks, checkMeta{
+			name:  checks.RuleGroupCheckName,
+			check: checks.NewRuleGroupCheck(p),
+		})
 	}
 
-	for _, rule := range cfg.Rules {
-		allChecks = append(allChecks, rule.resolveChecks(ctx, path, r, cfg.Checks.Enabled, cfg.Checks.Disabled, proms)...)
+	if r.DashboardQuery != nil {
+		allChecks = dashboardChecks(allChecks)
+	} else {
+		for _, rule := range cfg.Rules {
+			allChecks = append(allChecks, rule.resolveChecks(ctx, path, r, cfg.Checks.Enabled, cfg.Checks.Disabled, proms)...)
+		}
 	}
 
 	for _, cm := range allChecks {
@@ -164,12 +232,26 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 		name = r.AlertingRule.Alert.Value.Value
 	} else if r.RecordingRule != nil {
 		name = r.RecordingRule.Record.Value.Value
+	} else if r.DashboardQuery != nil {
+		name = r.DashboardQuery.Path
 	}
 	log.Debug().Strs("enabled", el).Str("path", path).Str("rule", name).Msg("Configured checks for rule")
 
 	return enabled
 }
 
+// dashboardChecks returns only checks that can be used with queries from
+// Grafana dashboards, these are checks that only look at PromQL.
+func dashboardChecks(all []checkMeta) (cms []checkMeta) {
+	for _, cm := range all {
+		switch cm.name {
+		case checks.SyntaxCheckName, checks.RateCheckName, checks.SeriesCheckName, checks.RegexpCheckName, checks.FragileCheckName:
+			cms = append(cms, cm)
+		}
+	}
+	return cms
+}
+
 func Load(path string, failOnMissing bool) (cfg Config, err error) {
 	cfg = Config{
 		CI: &CI{
@@ -222,16 +304,42 @@ func Load(path string, failOnMissing bool) (cfg Config, err error) {
 		}
 	}
 
+	var diskCache *promapi.DiskCache
+	if cfg.Cache != nil {
+		if err = cfg.Cache.validate(); err != nil {
+			return cfg, err
+		}
+		// A single cache is shared by all Prometheus servers, including failover
+		// upstreams, entries are keyed by each upstream URI.
+		diskCache = promapi.NewDiskCache(cfg.Cache.Path, cfg.Cache.ttls())
+	}
+
 	for _, prom := range cfg.Prometheus {
 		if err = prom.validate(); err != nil {
 			return cfg, err
 		}
 		timeout, _ := parseDuration(prom.Timeout)
+		// headers are resolved again for every request, so credentials
+		// can be rotated, but we still want to fail early if they are invalid
+		if _, err = prom.headers(); err != nil {
+			return cfg, err
+		}
+		var headers promapi.HeadersFunc
+		if len(prom.Headers) > 0 || prom.BasicAuth != nil || prom.BearerToken != nil {
+			headers = prom.headers
+		}
+		tlsConf, err := prom.tlsConfig()
+		if err != nil {
+			return cfg, err
+		}
 		upstreams := []*promapi.Prometheus{
-			promapi.NewPrometheus(prom.Name, prom.URI, timeout),
+			promapi.NewPrometheus(prom.Name, prom.URI, headers, timeout, tlsConf),
 		}
 		for _, uri := range prom.Failover {
-			upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, timeout))
+			upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, headers, timeout, tlsConf))
+		}
+		for _, upstream := range upstreams {
+			upstream.SetDiskCache(diskCache)
 		}
 		cfg.prometheusServers = append(cfg.prometheusServers, promapi.NewFailoverGroup(prom.Name, upstreams, prom.Required))
 	}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/parser"
 )
 
@@ -134,12 +135,23 @@ func newRule(t *testing.T, content string) parser.Rule {
 	return rules[0]
 }
 
+func newDashboardQuery(t *testing.T, content string) parser.Rule {
+	p := parser.NewParser()
+	rules, err := p.ParseDashboard([]byte(content))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return rules[0]
+}
+
 func TestGetChecksForRule(t *testing.T) {
 	type testCaseT struct {
 		title  string
 		config string
 		path   string
 		rule   parser.Rule
+		cmd    config.ContextCommandVal
 		checks []string
 	}
 
@@ -156,6 +168,12 @@ func TestGetChecksForRule(t *testing.T) {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -174,9 +192,19 @@ prometheus "prom" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom)",
+				checks.CounterCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
+				checks.LabelsConflictCheckName + "(prom)",
+				checks.RuleGroupCheckName + "(prom)",
 			},
 		},
 		{
@@ -196,9 +224,19 @@ prometheus "prom" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom)",
+				checks.CounterCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
+				checks.LabelsConflictCheckName + "(prom)",
+				checks.RuleGroupCheckName + "(prom)",
 			},
 		},
 		{
@@ -219,8 +257,10 @@ checks {
 			path: "rules.yml",
 			rule: newRule(t, `
 # pint disable promql/rate
+# pint disable promql/counter
 # pint disable promql/series
 # pint disable promql/vector_matching
+# pint disable labels/conflict
 - record: foo
   expr: sum(foo)
 `),
@@ -230,6 +270,12 @@ checks {
 				checks.ComparisonCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RuleGroupCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom2)",
 			},
 		},
 		{
@@ -250,6 +296,12 @@ prometheus "prom" {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -269,9 +321,19 @@ prometheus "prom" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom)",
+				checks.CounterCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
+				checks.LabelsConflictCheckName + "(prom)",
+				checks.RuleGroupCheckName + "(prom)",
 			},
 		},
 		{
@@ -296,9 +358,19 @@ prometheus "ignore" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom)",
+				checks.CounterCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
+				checks.LabelsConflictCheckName + "(prom)",
+				checks.RuleGroupCheckName + "(prom)",
 			},
 		},
 		{
@@ -313,6 +385,12 @@ prometheus "ignore" {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -336,7 +414,14 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.AggregationCheckName + "(job:true)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AggregationCheckName + "(job:true)",
 				checks.AggregationCheckName + "(instance:false)",
 				checks.AggregationCheckName + "(rack:false)",
 			},
@@ -366,7 +451,14 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.AggregationCheckName + "(job:true)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AggregationCheckName + "(job:true)",
 				checks.AggregationCheckName + "(rack:false)",
 			},
 		},
@@ -390,6 +482,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -426,9 +524,22 @@ prometheus "prom2" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom1)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom1)",
+				checks.CounterCheckName + "(prom1)",
+				checks.LabelsConflictCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom1)",
+				checks.CounterCheckName + "(prom2)",
 				checks.SeriesCheckName + "(prom2)",
 				checks.VectorMatchingCheckName + "(prom2)",
+				checks.LabelsConflictCheckName + "(prom2)",
+				checks.RuleGroupCheckName + "(prom2)",
 				checks.CostCheckName + "(prom1)",
 			},
 		},
@@ -473,7 +584,14 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.LabelCheckName + "(team:true)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.LabelCheckName + "(team:true)",
 				checks.AnnotationCheckName + "(summary:true)",
 				checks.LabelCheckName + "(team:false)",
 				checks.AnnotationCheckName + "(summary=~^foo.+$:true)",
@@ -529,6 +647,18 @@ rule {
 				checks.AlertForCheckName,
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.CounterCheckName + "(prom1)",
+				checks.LabelsConflictCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom1)",
+				checks.CounterCheckName + "(prom2)",
+				checks.LabelsConflictCheckName + "(prom2)",
+				checks.RuleGroupCheckName + "(prom2)",
 				checks.CostCheckName + "(prom1)",
 				checks.CostCheckName + "(prom2)",
 				checks.CostCheckName + "(prom1:10000)",
@@ -563,12 +693,208 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RejectCheckName + "(key=~'^http://.+$')",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RejectCheckName + "(key=~'^http://.+$')",
 				checks.RejectCheckName + "(val=~'^http://.+$')",
 				checks.RejectCheckName + "(key=~'^.* +.*$')",
 				checks.RejectCheckName + "(val=~'^$')",
 			},
 		},
+		{
+			title: "naming rules",
+			config: `
+rule {
+  match {
+    kind = "recording"
+  }
+  naming {
+    level      = true
+    operations = true
+    severity   = "bug"
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.NamingCheckName,
+			},
+		},
+		{
+			title: "unused rules",
+			config: `
+rule {
+  match {
+    kind = "recording"
+  }
+  unused {
+    sources  = [ "dashboards/*.json" ]
+    severity = "info"
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RuleUnusedCheckName,
+			},
+		},
+		{
+			title: "unused rules / ci",
+			config: `
+rule {
+  match {
+    kind = "recording"
+  }
+  unused {}
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			cmd:  config.CICommand,
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "unused rules / multiple prometheus servers",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost/1"
+  timeout = "1s"
+}
+prometheus "prom2" {
+  uri     = "http://localhost/2"
+  timeout = "1s"
+}
+rule {
+  unused {}
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom1)",
+				checks.CounterCheckName + "(prom1)",
+				checks.SeriesCheckName + "(prom1)",
+				checks.VectorMatchingCheckName + "(prom1)",
+				checks.LabelsConflictCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom1)",
+				checks.RateCheckName + "(prom2)",
+				checks.CounterCheckName + "(prom2)",
+				checks.SeriesCheckName + "(prom2)",
+				checks.VectorMatchingCheckName + "(prom2)",
+				checks.LabelsConflictCheckName + "(prom2)",
+				checks.RuleGroupCheckName + "(prom2)",
+				checks.RuleUnusedCheckName + "(prom1)",
+				checks.RuleUnusedCheckName + "(prom2)",
+			},
+		},
+		{
+			title: "tested alerts",
+			config: `
+rule {
+  match {
+    kind = "alerting"
+  }
+  tests {
+    files = [ "tests/*.yml" ]
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- alert: foo\n  expr: sum(foo) > 0\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AlertsTestedCheckName,
+			},
+		},
+		{
+			title: "dashboard query",
+			config: `
+prometheus "prom" {
+  uri     = "http://localhost"
+  timeout = "1s"
+}
+rule {
+  cost {}
+  naming {}
+}
+`,
+			path: "dashboards/foo.json",
+			rule: newDashboardQuery(t, `{"panels": [{"targets": [{"expr": "sum(rate(foo[5m]))"}]}]}`),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.RateCheckName + "(prom)",
+				checks.SeriesCheckName + "(prom)",
+			},
+		},
 		{
 			title: "rule with label match / type mismatch",
 			config: `
@@ -595,6 +921,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -623,6 +955,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -651,6 +989,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -678,7 +1022,14 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.LabelCheckName + "(priority:true)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.LabelCheckName + "(priority:true)",
 			},
 		},
 		{
@@ -707,6 +1058,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -735,6 +1092,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -762,7 +1125,14 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.LabelCheckName + "(priority:true)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.LabelCheckName + "(priority:true)",
 			},
 		},
 		{
@@ -778,6 +1148,7 @@ rule {
 checks {
   disabled = [
     "promql/rate",
+    "promql/counter",
 	"promql/vector_matching",
   ]
 }
@@ -799,7 +1170,15 @@ prometheus "prom1" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.AlertsCheckName + "(prom1)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.LabelsConflictCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom1)",
+				checks.AlertsCheckName + "(prom1)",
 			},
 		},
 		{
@@ -832,9 +1211,19 @@ prometheus "prom1" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom1)",
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom1)",
+				checks.CounterCheckName + "(prom1)",
 				checks.SeriesCheckName + "(prom1)",
 				checks.VectorMatchingCheckName + "(prom1)",
+				checks.LabelsConflictCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom1)",
 				checks.AlertsCheckName + "(prom1)",
 			},
 		},
@@ -960,6 +1349,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 				checks.AnnotationCheckName + "(summary:true)",
 			},
 		},
@@ -984,6 +1379,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -1007,6 +1408,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -1030,6 +1437,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 				checks.AnnotationCheckName + "(summary:true)",
 			},
 		},
@@ -1054,6 +1467,12 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 			},
 		},
 		{
@@ -1077,77 +1496,610 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
 				checks.AnnotationCheckName + "(summary:true)",
 			},
 		},
-	}
-
-	dir := t.TempDir()
-	ctx := context.WithValue(context.Background(), config.CommandKey, config.LintCommand)
-	for i, tc := range testCases {
-		t.Run(tc.title, func(t *testing.T) {
-			assert := assert.New(t)
-
-			path := path.Join(dir, fmt.Sprintf("%d.hcl", i))
-			if tc.config != "" {
-				err := ioutil.WriteFile(path, []byte(tc.config), 0o644)
-				assert.NoError(err)
-			}
-
-			cfg, err := config.Load(path, false)
-			assert.NoError(err)
-
-			checks := cfg.GetChecksForRule(ctx, tc.path, tc.rule)
-			checkNames := make([]string, 0, len(checks))
-			for _, c := range checks {
-				checkNames = append(checkNames, c.String())
-			}
-			assert.Equal(tc.checks, checkNames)
-			snaps.MatchSnapshot(t, cfg.String())
-		})
-	}
-}
-
-func TestConfigErrors(t *testing.T) {
-	type testCaseT struct {
-		config string
-		err    string
-	}
-
-	testCases := []testCaseT{
-		{
-			config: "ci {maxCommits = -1}",
-			err:    "maxCommits cannot be <= 0",
-		},
-		{
-			config: `ci {include = [".+", ".+++"]}`,
-			err:    "error parsing regexp: invalid nested repetition operator: `++`",
-		},
 		{
-			config: `repository {
-  bitbucket {
-    project    = ""
-    repository = ""
-    timeout    = ""
-	uri        = ""
+			title: "group match / passing",
+			config: `
+rule {
+  match {
+	group = "fo+"
   }
-}`,
-			err: "empty duration string",
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
 		},
 		{
-			config: `repository {
-  github {
-    baseuri = ""
-    timeout = ""
-    owner   = ""
-    repo    = ""
+			title: "group match / not passing",
+			config: `
+rule {
+  match {
+	group = "bar"
   }
-}`,
-			err: "empty duration string",
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
 		},
 		{
-			config: `checks { enabled = ["foo"] }`,
-			err:    "unknown check name foo",
+			title: "group match / no group / not passing",
+			config: `
+rule {
+  match {
+	group = ".*"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- alert: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "interval match / passing",
+			config: `
+rule {
+  match {
+	interval = ">= 2m"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
+		},
+		{
+			title: "interval match / not passing",
+			config: `
+rule {
+  match {
+	interval = "< 2m"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "interval match / default interval / passing",
+			config: `
+rule {
+  match {
+	interval = "<= 1m"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
+		},
+		{
+			title: "interval match / default interval / not passing",
+			config: `
+rule {
+  match {
+	interval = "> 1m"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "interval match / invalid group interval",
+			config: `
+rule {
+  match {
+	interval = "> 0"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  interval: bob\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "interval ignore / no group / passing",
+			config: `
+rule {
+  ignore {
+	interval = "> 0"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- alert: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
+		},
+		{
+			title: "namespace match / passing",
+			config: `
+rule {
+  match {
+	namespace = "fo+"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
+		},
+		{
+			title: "namespace match / no namespace / not passing",
+			config: `
+rule {
+  match {
+	namespace = ".*"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "tenant match / passing",
+			config: `
+rule {
+  match {
+	tenant = "b"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
+		},
+		{
+			title: "tenant match / not passing",
+			config: `
+rule {
+  match {
+	tenant = "c"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+		{
+			title: "prometheus tenants / match",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost/1"
+  timeout = "1s"
+  tenants = ["a"]
+}
+prometheus "prom2" {
+  uri     = "http://localhost/2"
+  timeout = "1s"
+  tenants = ["c", "d"]
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom1)",
+				checks.CounterCheckName + "(prom1)",
+				checks.SeriesCheckName + "(prom1)",
+				checks.VectorMatchingCheckName + "(prom1)",
+				checks.LabelsConflictCheckName + "(prom1)",
+				checks.RuleGroupCheckName + "(prom1)",
+			},
+		},
+		{
+			title: "prometheus tenants / no source_tenants",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost/1"
+  timeout = "1s"
+  tenants = [".*"]
+}
+prometheus "prom2" {
+  uri     = "http://localhost/2"
+  timeout = "1s"
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "namespace: foo\ngroups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+				checks.RateCheckName + "(prom2)",
+				checks.CounterCheckName + "(prom2)",
+				checks.SeriesCheckName + "(prom2)",
+				checks.VectorMatchingCheckName + "(prom2)",
+				checks.LabelsConflictCheckName + "(prom2)",
+				checks.RuleGroupCheckName + "(prom2)",
+			},
+		},
+		{
+			title: "prometheus tenants / no tenants",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost/1"
+  timeout = "1s"
+  tenants = [".*"]
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- alert: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.HistogramCheckName,
+				checks.VectorMatchingCheckName,
+				checks.LabelsConflictCheckName,
+				checks.RuleGroupCheckName,
+				checks.RuleDuplicateCheckName,
+				checks.RuleDependencyCheckName,
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	ctx := context.WithValue(context.Background(), config.CommandKey, config.LintCommand)
+	for i, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			assert := assert.New(t)
+
+			path := path.Join(dir, fmt.Sprintf("%d.hcl", i))
+			if tc.config != "" {
+				err := ioutil.WriteFile(path, []byte(tc.config), 0o644)
+				assert.NoError(err)
+			}
+
+			cfg, err := config.Load(path, false)
+			assert.NoError(err)
+
+			ctx := ctx
+			if tc.cmd != "" {
+				ctx = context.WithValue(ctx, config.CommandKey, tc.cmd)
+			}
+			checks := cfg.GetChecksForRule(ctx, tc.path, tc.rule)
+			checkNames := make([]string, 0, len(checks))
+			for _, c := range checks {
+				checkNames = append(checkNames, c.String())
+			}
+			assert.Equal(tc.checks, checkNames)
+			snaps.MatchSnapshot(t, cfg.String())
+		})
+	}
+}
+
+func TestRuleDuplicateCheckPrometheusPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := path.Join(dir, "config.hcl")
+	err := ioutil.WriteFile(path, []byte(`
+prometheus "a" {
+  uri     = "http://localhost/a"
+  timeout = "1s"
+  paths   = [ "a/.+" ]
+}
+prometheus "b" {
+  uri     = "http://localhost/b"
+  timeout = "1s"
+  paths   = [ "b/.+" ]
+}
+`), 0o644)
+	assert.NoError(t, err)
+
+	cfg, err := config.Load(path, false)
+	assert.NoError(t, err)
+
+	rule := newRule(t, "- record: foo\n  expr: sum(foo)\n")
+	var check checks.RuleChecker
+	for _, c := range cfg.GetChecksForRule(context.Background(), "a/1.yml", rule) {
+		if c.String() == checks.RuleDuplicateCheckName {
+			check = c
+		}
+	}
+	assert.NotNil(t, check)
+
+	entries := []discovery.Entry{
+		{Path: "a/1.yml", Rule: rule},
+		{Path: "b/1.yml", Rule: newRule(t, "- record: foo\n  expr: sum(foo)\n")},
+	}
+	assert.Empty(t, check.Check(context.Background(), rule, entries))
+
+	entries = append(entries, discovery.Entry{Path: "a/2.yml", Rule: newRule(t, "- record: foo\n  expr: sum(foo)\n")})
+	problems := check.Check(context.Background(), rule, entries)
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Text, "is also defined in a/2.yml on line 1")
+}
+
+func TestConfigErrors(t *testing.T) {
+	type testCaseT struct {
+		config string
+		err    string
+	}
+
+	testCases := []testCaseT{
+		{
+			config: "ci {maxCommits = -1}",
+			err:    "maxCommits cannot be <= 0",
+		},
+		{
+			config: `ci {include = [".+", ".+++"]}`,
+			err:    "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `repository {
+  bitbucket {
+    project    = ""
+    repository = ""
+    timeout    = ""
+	uri        = ""
+  }
+}`,
+			err: "empty duration string",
+		},
+		{
+			config: `repository {
+  github {
+    baseuri = ""
+    timeout = ""
+    owner   = ""
+    repo    = ""
+  }
+}`,
+			err: "empty duration string",
+		},
+		{
+			config: `checks { enabled = ["foo"] }`,
+			err:    "unknown check name foo",
 		},
 		{
 			config: `prometheus "prom" {
@@ -1156,6 +2108,85 @@ func TestConfigErrors(t *testing.T) {
 }`,
 			err: `not a valid duration string: "abc"`,
 		},
+		{
+			config: `cache {
+  path  = ".pint/cache"
+  range = "abc"
+}`,
+			err: `not a valid duration string: "abc"`,
+		},
+		{
+			config: `cache {
+  path  = ".pint/cache"
+  query = "0s"
+}`,
+			err: "cache query duration must be > 0",
+		},
+		{
+			config: `cache {
+  path   = ".pint/cache"
+  series = "0"
+}`,
+			err: "cache series duration must be > 0",
+		},
+		{
+			config: `cache {
+  path  = ".pint/cache"
+  range = "-1h"
+}`,
+			err: `not a valid duration string: "-1h"`,
+		},
+		{
+			config: `cache {
+  path = ""
+}`,
+			err: "cache path cannot be empty",
+		},
+		{
+			config: `prometheus "prom" {
+  uri     = "http://localhost"
+  timeout = "30s"
+  bearer_token {
+    file = "/this/file/does/not/exist"
+  }
+}`,
+			err: "failed to read bearer_token file: open /this/file/does/not/exist: no such file or directory",
+		},
+		{
+			config: `prometheus "prom" {
+  uri     = "http://localhost"
+  timeout = "30s"
+  tls {
+    key_file = "key.pem"
+  }
+}`,
+			err: "both cert_file and key_file must be set in tls",
+		},
+		{
+			config: `rule {
+  naming {
+    severity = "xxx"
+  }
+}`,
+			err: "unknown severity: xxx",
+		},
+		{
+			config: `rule {
+  unused {
+    sources = [ "[" ]
+  }
+}`,
+			err: "syntax error in pattern",
+		},
+		{
+			config: `rule {
+  tests {
+    files    = [ "tests/*.yml" ]
+    severity = "xxx"
+  }
+}`,
+			err: "unknown severity: xxx",
+		},
 		{
 			config: `rule {
   aggregate ".+++" {}
@@ -1273,12 +2304,58 @@ func TestConfigErrors(t *testing.T) {
   match {
 	for = "!1s"
   }
+}`,
+			err: `not a valid duration string: "!1s"`,
+		},
+		{
+			config: `rule {
+  match {
+	group = ".+++"
+  }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
+  match {
+	interval = "!1s"
+  }
 }`,
 			err: `not a valid duration string: "!1s"`,
 		},
 		{
 			config: `parser {
   relaxed = ["foo", ".+", "(.+++)"]
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `parser {
+  ruler = ["foo", ".+", "(.+++)"]
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
+  match {
+	namespace = ".+++"
+  }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
+  match {
+	tenant = ".+++"
+  }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `prometheus "prom" {
+  uri     = "http://localhost"
+  timeout = "1s"
+  tenants = [".+++"]
 }`,
 			err: "error parsing regexp: invalid nested repetition operator: `++`",
 		},
@@ -11,9 +11,13 @@ import (
 )
 
 const (
-	AlertingRuleType  = "alerting"
-	RecordingRuleType = "recording"
-	InvalidRuleType   = "invalid"
+	AlertingRuleType   = "alerting"
+	RecordingRuleType  = "recording"
+	DashboardQueryType = "dashboard"
+	InvalidRuleType    = "invalid"
+
+	// Prometheus default for groups without interval.
+	defaultGroupInterval = time.Minute
 )
 
 type (
@@ -33,6 +37,10 @@ type Match struct {
 	Name       string             `hcl:"name,optional" json:"name,omitempty"`
 	Kind       string             `hcl:"kind,optional" json:"kind,omitempty"`
 	For        string             `hcl:"for,optional" json:"for,omitempty"`
+	Group      string             `hcl:"group,optional" json:"group,omitempty"`
+	Interval   string             `hcl:"interval,optional" json:"interval,omitempty"`
+	Namespace  string             `hcl:"namespace,optional" json:"namespace,omitempty"`
+	Tenant     string             `hcl:"tenant,optional" json:"tenant,omitempty"`
 	Label      *MatchLabel        `hcl:"label,block" json:"label,omitempty"`
 	Annotation *MatchAnnotation   `hcl:"annotation,block" json:"annotation,omitempty"`
 	Command    *ContextCommandVal `hcl:"command,optional" json:"command,omitempty"`
@@ -74,7 +82,25 @@ func (m Match) validate(allowEmpty bool) error {
 		}
 	}
 
-	if !allowEmpty && m.Path == "" && m.Name == "" && m.Kind == "" && m.Label == nil && m.Annotation == nil && m.Command == nil && m.For == "" {
+	if _, err := regexp.Compile(m.Group); err != nil {
+		return err
+	}
+
+	if m.Interval != "" {
+		if _, err := parseDurationMatch(m.Interval); err != nil {
+			return err
+		}
+	}
+
+	if _, err := regexp.Compile(m.Namespace); err != nil {
+		return err
+	}
+
+	if _, err := regexp.Compile(m.Tenant); err != nil {
+		return err
+	}
+
+	if !allowEmpty && m.Path == "" && m.Name == "" && m.Kind == "" && m.Label == nil && m.Annotation == nil && m.Command == nil && m.For == "" && m.Group == "" && m.Interval == "" && m.Namespace == "" && m.Tenant == "" {
 		return fmt.Errorf("ignore block must have at least one condition")
 	}
 
@@ -140,9 +166,64 @@ func (m Match) IsMatch(ctx context.Context, path string, r parser.Rule) bool {
 		}
 	}
 
+	if m.Group != "" {
+		if r.Group == nil {
+			return false
+		}
+		re := strictRegex(m.Group)
+		if !re.MatchString(r.Group.Name.Value.Value) {
+			return false
+		}
+	}
+
+	if m.Interval != "" {
+		if r.Group == nil {
+			return false
+		}
+		dm, _ := parseDurationMatch(m.Interval)
+		dur := defaultGroupInterval
+		if r.Group.Interval != nil {
+			var err error
+			if dur, err = parseDuration(r.Group.Interval.Value.Value); err != nil {
+				return false
+			}
+		}
+		if !dm.isMatch(dur) {
+			return false
+		}
+	}
+
+	if m.Namespace != "" {
+		if r.Group == nil || r.Group.Namespace == nil {
+			return false
+		}
+		re := strictRegex(m.Namespace)
+		if !re.MatchString(r.Group.Namespace.Value.Value) {
+			return false
+		}
+	}
+
+	if m.Tenant != "" {
+		if r.Group == nil || !matchesAnyTenant([]string{m.Tenant}, r.Group.Tenants()) {
+			return false
+		}
+	}
+
 	return true
 }
 
+func matchesAnyTenant(patterns, tenants []string) bool {
+	for _, pattern := range patterns {
+		re := strictRegex(pattern)
+		for _, tenant := range tenants {
+			if re.MatchString(tenant) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type MatchLabel struct {
 	Key   string `hcl:",label" json:"key"`
 	Value string `hcl:"value" json:"value"`
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type NamingSettings struct {
+	Level      bool   `hcl:"level,optional" json:"level,omitempty"`
+	Operations bool   `hcl:"operations,optional" json:"operations,omitempty"`
+	Severity   string `hcl:"severity,optional" json:"severity,omitempty"`
+}
+
+func (ns NamingSettings) validate() error {
+	if ns.Severity != "" {
+		if _, err := checks.ParseSeverity(ns.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ns NamingSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ns.Severity != "" {
+		sev, _ := checks.ParseSeverity(ns.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingSettings(t *testing.T) {
+	type testCaseT struct {
+		conf NamingSettings
+		err  error
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: NamingSettings{},
+		},
+		{
+			conf: NamingSettings{
+				Level:      true,
+				Operations: true,
+				Severity:   "bug",
+			},
+		},
+		{
+			conf: NamingSettings{
+				Severity: "foo",
+			},
+			err: errors.New("unknown severity: foo"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			assert := assert.New(t)
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				assert.Equal(err, tc.err)
+			} else {
+				assert.EqualError(err, tc.err.Error())
+			}
+		})
+	}
+}
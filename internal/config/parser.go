@@ -5,7 +5,9 @@ import (
 )
 
 type Parser struct {
-	Relaxed []string `hcl:"relaxed,optional" json:"relaxed,omitempty"`
+	Relaxed    []string `hcl:"relaxed,optional" json:"relaxed,omitempty"`
+	Ruler      []string `hcl:"ruler,optional" json:"ruler,omitempty"`
+	Dashboards []string `hcl:"dashboards,optional" json:"dashboards,omitempty"`
 }
 
 func (p Parser) validate() error {
@@ -15,6 +17,18 @@ func (p Parser) validate() error {
 			return err
 		}
 	}
+	for _, pattern := range p.Ruler {
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+	}
+	for _, pattern := range p.Dashboards {
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -24,3 +38,17 @@ func (p Parser) CompileRelaxed() (r []*regexp.Regexp) {
 	}
 	return
 }
+
+func (p Parser) CompileRuler() (r []*regexp.Regexp) {
+	for _, pattern := range p.Ruler {
+		r = append(r, regexp.MustCompile("^"+pattern+"$"))
+	}
+	return
+}
+
+func (p Parser) CompileDashboards() (r []*regexp.Regexp) {
+	for _, pattern := range p.Dashboards {
+		r = append(r, regexp.MustCompile("^"+pattern+"$"))
+	}
+	return
+}
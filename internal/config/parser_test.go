@@ -29,6 +29,17 @@ func TestParserSettings(t *testing.T) {
 			},
 			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
 		},
+		{
+			conf: Parser{
+				Dashboards: []string{"dashboards/.+[.]json"},
+			},
+		},
+		{
+			conf: Parser{
+				Dashboards: []string{"(.+++)"},
+			},
+			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1,8 +1,18 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
 	"regexp"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
 )
 
 type PrometheusConfig struct {
@@ -11,7 +21,135 @@ type PrometheusConfig struct {
 	Failover []string `hcl:"failover,optional" json:"failover,omitempty"`
 	Timeout  string   `hcl:"timeout"  json:"timeout"`
 	Paths    []string `hcl:"paths,optional" json:"paths,omitempty"`
+	Tenants  []string `hcl:"tenants,optional" json:"tenants,omitempty"`
 	Required bool     `hcl:"required,optional" json:"required"`
+
+	Headers     Headers      `hcl:"headers,optional" json:"headers,omitempty"`
+	BasicAuth   *BasicAuth   `hcl:"basic_auth,block" json:"basic_auth,omitempty"`
+	BearerToken *BearerToken `hcl:"bearer_token,block" json:"bearer_token,omitempty"`
+	TLS         *TLSConfig   `hcl:"tls,block" json:"tls,omitempty"`
+}
+
+// Headers holds extra HTTP headers to set on requests.
+// Header values often contain credentials, so they are redacted
+// when the config is printed.
+type Headers map[string]string
+
+func (h Headers) MarshalJSON() ([]byte, error) {
+	redacted := make(map[string]string, len(h))
+	for k := range h {
+		redacted[k] = "***"
+	}
+	return json.Marshal(redacted)
+}
+
+type BasicAuth struct {
+	Username     string `hcl:"username" json:"username"`
+	Password     string `hcl:"password,optional" json:"-"`
+	PasswordFile string `hcl:"password_file,optional" json:"password_file,omitempty"`
+	PasswordEnv  string `hcl:"password_env,optional" json:"password_env,omitempty"`
+}
+
+func (ba BasicAuth) validate() error {
+	if ba.Username == "" {
+		return errors.New("basic_auth username cannot be empty")
+	}
+	if countSet(ba.Password, ba.PasswordFile, ba.PasswordEnv) > 1 {
+		return errors.New("only one of password, password_file and password_env can be set in basic_auth")
+	}
+	return nil
+}
+
+func (ba BasicAuth) header() (string, error) {
+	password := ba.Password
+	switch {
+	case ba.PasswordFile != "":
+		content, err := os.ReadFile(ba.PasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read basic_auth password file: %w", err)
+		}
+		password = strings.TrimSpace(string(content))
+	case ba.PasswordEnv != "":
+		password = os.Getenv(ba.PasswordEnv)
+		if password == "" {
+			return "", fmt.Errorf("basic_auth password environment variable %q is not set", ba.PasswordEnv)
+		}
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(ba.Username+":"+password)), nil
+}
+
+type BearerToken struct {
+	File string `hcl:"file,optional" json:"file,omitempty"`
+	Env  string `hcl:"env,optional" json:"env,omitempty"`
+}
+
+func (bt BearerToken) validate() error {
+	if countSet(bt.File, bt.Env) != 1 {
+		return errors.New("exactly one of file and env must be set in bearer_token")
+	}
+	return nil
+}
+
+func (bt BearerToken) header() (string, error) {
+	var token string
+	if bt.File != "" {
+		content, err := os.ReadFile(bt.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read bearer_token file: %w", err)
+		}
+		token = strings.TrimSpace(string(content))
+	} else {
+		token = os.Getenv(bt.Env)
+	}
+	if token == "" {
+		return "", errors.New("bearer_token cannot be empty")
+	}
+	return "Bearer " + token, nil
+}
+
+type TLSConfig struct {
+	CAFile             string `hcl:"ca_file,optional" json:"ca_file,omitempty"`
+	CertFile           string `hcl:"cert_file,optional" json:"cert_file,omitempty"`
+	KeyFile            string `hcl:"key_file,optional" json:"key_file,omitempty"`
+	ServerName         string `hcl:"server_name,optional" json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `hcl:"insecure_skip_verify,optional" json:"insecure_skip_verify,omitempty"`
+}
+
+func (tc TLSConfig) validate() error {
+	if (tc.CertFile == "") != (tc.KeyFile == "") {
+		return errors.New("both cert_file and key_file must be set in tls")
+	}
+	return nil
+}
+
+func (tc TLSConfig) toTLSConfig() (*tls.Config, error) {
+	conf := &tls.Config{
+		ServerName:         tc.ServerName,
+		InsecureSkipVerify: tc.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if tc.CAFile != "" {
+		content, err := os.ReadFile(tc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("no valid certificates found in tls ca_file %q", tc.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if tc.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
 }
 
 func (pc PrometheusConfig) validate() error {
@@ -19,19 +157,85 @@ func (pc PrometheusConfig) validate() error {
 		return errors.New("prometheus URI cannot be empty")
 	}
 
+	for _, uri := range append([]string{pc.URI}, pc.Failover...) {
+		if path, ok := promapi.FixturePath(uri); ok && path == "" {
+			return fmt.Errorf("fixture file path cannot be empty in %q", uri)
+		}
+	}
+
 	if _, err := parseDuration(pc.Timeout); err != nil {
 		return err
 	}
 
+	if pc.BasicAuth != nil {
+		if err := pc.BasicAuth.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.BearerToken != nil {
+		if err := pc.BearerToken.validate(); err != nil {
+			return err
+		}
+		if pc.BasicAuth != nil {
+			return errors.New("basic_auth and bearer_token cannot be both set")
+		}
+	}
+
+	if pc.TLS != nil {
+		if err := pc.TLS.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, path := range pc.Paths {
 		if _, err := regexp.Compile(path); err != nil {
 			return err
 		}
 	}
 
+	for _, tenant := range pc.Tenants {
+		if _, err := regexp.Compile(tenant); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// headers returns all HTTP headers that should be set on requests
+// to this Prometheus server, including the Authorization header.
+func (pc PrometheusConfig) headers() (map[string]string, error) {
+	headers := map[string]string{}
+	for k, v := range pc.Headers {
+		headers[k] = v
+	}
+
+	var auth string
+	var err error
+	switch {
+	case pc.BasicAuth != nil:
+		auth, err = pc.BasicAuth.header()
+	case pc.BearerToken != nil:
+		auth, err = pc.BearerToken.header()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if auth != "" {
+		headers["Authorization"] = auth
+	}
+
+	return headers, nil
+}
+
+func (pc PrometheusConfig) tlsConfig() (*tls.Config, error) {
+	if pc.TLS == nil {
+		return nil, nil
+	}
+	return pc.TLS.toTLSConfig()
+}
+
 func (pc PrometheusConfig) isEnabledForPath(path string) bool {
 	if len(pc.Paths) == 0 {
 		return true
@@ -44,3 +248,22 @@ func (pc PrometheusConfig) isEnabledForPath(path string) bool {
 	}
 	return false
 }
+
+func (pc PrometheusConfig) isEnabledForRule(rule parser.Rule) bool {
+	if len(pc.Tenants) == 0 {
+		return true
+	}
+	if rule.Group == nil {
+		return false
+	}
+	return matchesAnyTenant(pc.Tenants, rule.Group.Tenants())
+}
+
+func countSet(values ...string) (n int) {
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPrometheusConfig(t *testing.T) {
@@ -32,6 +36,23 @@ func TestPrometheusConfig(t *testing.T) {
 				Paths:    []string{"foo", "bar"},
 			},
 		},
+		{
+			conf: PrometheusConfig{
+				Name:     "prom",
+				URI:      "file://fixtures/prod.om",
+				Failover: []string{"http://localhost"},
+				Timeout:  "5m",
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				Name:     "prom",
+				URI:      "http://localhost",
+				Failover: []string{"file://"},
+				Timeout:  "5m",
+			},
+			err: errors.New(`fixture file path cannot be empty in "file://"`),
+		},
 		{
 			conf: PrometheusConfig{URI: "http://localhost"},
 			err:  errors.New("empty duration string"),
@@ -56,6 +77,71 @@ func TestPrometheusConfig(t *testing.T) {
 			},
 			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
 		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				Timeout:   "5m",
+				Headers:   map[string]string{"X-Scope-OrgID": "tenant"},
+				BasicAuth: &BasicAuth{Username: "foo", PasswordEnv: "FOO"},
+				TLS:       &TLSConfig{CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem"},
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				Timeout:   "5m",
+				BasicAuth: &BasicAuth{Password: "bar"},
+			},
+			err: errors.New("basic_auth username cannot be empty"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				Timeout:   "5m",
+				BasicAuth: &BasicAuth{Username: "foo", Password: "bar", PasswordFile: "password.txt"},
+			},
+			err: errors.New("only one of password, password_file and password_env can be set in basic_auth"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				BearerToken: &BearerToken{},
+			},
+			err: errors.New("exactly one of file and env must be set in bearer_token"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				BearerToken: &BearerToken{File: "token.txt", Env: "TOKEN"},
+			},
+			err: errors.New("exactly one of file and env must be set in bearer_token"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				BasicAuth:   &BasicAuth{Username: "foo"},
+				BearerToken: &BearerToken{Env: "TOKEN"},
+			},
+			err: errors.New("basic_auth and bearer_token cannot be both set"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				TLS:     &TLSConfig{CertFile: "cert.pem"},
+			},
+			err: errors.New("both cert_file and key_file must be set in tls"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -70,3 +156,136 @@ func TestPrometheusConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusConfigHeaders(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o644))
+	t.Setenv("PINT_TEST_PASSWORD", "bar")
+	t.Setenv("PINT_TEST_TOKEN", "")
+
+	type testCaseT struct {
+		conf    PrometheusConfig
+		headers map[string]string
+		err     string
+	}
+
+	testCases := []testCaseT{
+		{
+			conf:    PrometheusConfig{},
+			headers: map[string]string{},
+		},
+		{
+			conf: PrometheusConfig{
+				Headers:   map[string]string{"X-Scope-OrgID": "tenant"},
+				BasicAuth: &BasicAuth{Username: "foo", Password: "bar"},
+			},
+			headers: map[string]string{
+				"X-Scope-OrgID": "tenant",
+				"Authorization": "Basic Zm9vOmJhcg==",
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				BasicAuth: &BasicAuth{Username: "foo", PasswordEnv: "PINT_TEST_PASSWORD"},
+			},
+			headers: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="},
+		},
+		{
+			conf: PrometheusConfig{
+				BasicAuth: &BasicAuth{Username: "foo", PasswordEnv: "PINT_TEST_TOKEN"},
+			},
+			err: `basic_auth password environment variable "PINT_TEST_TOKEN" is not set`,
+		},
+		{
+			conf: PrometheusConfig{
+				BasicAuth: &BasicAuth{Username: "foo", PasswordFile: filepath.Join(dir, "missing")},
+			},
+			err: "failed to read basic_auth password file: open " + filepath.Join(dir, "missing") + ": no such file or directory",
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{File: tokenFile},
+			},
+			headers: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{Env: "PINT_TEST_TOKEN"},
+			},
+			err: "bearer_token cannot be empty",
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{File: filepath.Join(dir, "missing")},
+			},
+			err: "failed to read bearer_token file: open " + filepath.Join(dir, "missing") + ": no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			headers, err := tc.conf.headers()
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.headers, headers)
+			}
+		})
+	}
+}
+
+func TestPrometheusConfigHeadersRotation(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o644))
+
+	conf := PrometheusConfig{BearerToken: &BearerToken{File: tokenFile}}
+	headers, err := conf.headers()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Authorization": "Bearer secret"}, headers)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("rotated\n"), 0o644))
+	headers, err = conf.headers()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Authorization": "Bearer rotated"}, headers)
+}
+
+func TestPrometheusConfigTLS(t *testing.T) {
+	dir := t.TempDir()
+	badFile := filepath.Join(dir, "bad.pem")
+	require.NoError(t, os.WriteFile(badFile, []byte("not a certificate"), 0o644))
+
+	conf, err := PrometheusConfig{}.tlsConfig()
+	require.NoError(t, err)
+	require.Nil(t, conf)
+
+	conf, err = PrometheusConfig{TLS: &TLSConfig{ServerName: "prom.example.com", InsecureSkipVerify: true}}.tlsConfig()
+	require.NoError(t, err)
+	require.Equal(t, "prom.example.com", conf.ServerName)
+	require.True(t, conf.InsecureSkipVerify)
+	require.Nil(t, conf.RootCAs)
+	require.Empty(t, conf.Certificates)
+
+	_, err = PrometheusConfig{TLS: &TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}}.tlsConfig()
+	require.EqualError(t, err, "failed to read tls ca_file: open "+filepath.Join(dir, "missing.pem")+": no such file or directory")
+
+	_, err = PrometheusConfig{TLS: &TLSConfig{CAFile: badFile}}.tlsConfig()
+	require.EqualError(t, err, fmt.Sprintf("no valid certificates found in tls ca_file %q", badFile))
+
+	_, err = PrometheusConfig{TLS: &TLSConfig{CertFile: badFile, KeyFile: badFile}}.tlsConfig()
+	require.EqualError(t, err, "failed to load tls client certificate: tls: failed to find any PEM data in certificate input")
+}
+
+func TestPrometheusConfigHeadersRedacted(t *testing.T) {
+	conf := PrometheusConfig{
+		Name:    "prom",
+		URI:     "http://localhost",
+		Timeout: "1m",
+		Headers: Headers{"X-Api-Key": "secret"},
+	}
+	out, err := json.Marshal(conf)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"headers":{"X-Api-Key":"***"}`)
+	require.NotContains(t, string(out), "secret")
+}
@@ -34,7 +34,7 @@ func TestRejectSettings(t *testing.T) {
 			conf: RejectSettings{
 				Regex: "{{nil}}",
 			},
-			err: errors.New(`template: regexp:1:125: executing "regexp" at <nil>: nil is not a command`),
+			err: errors.New(`template: regexp:1:165: executing "regexp" at <nil>: nil is not a command`),
 		},
 		{
 			conf: RejectSettings{
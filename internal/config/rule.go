@@ -22,6 +22,9 @@ type Rule struct {
 	Cost       *CostSettings        `hcl:"cost,block" json:"cost,omitempty"`
 	Alerts     *AlertsSettings      `hcl:"alerts,block" json:"alerts,omitempty"`
 	Reject     []RejectSettings     `hcl:"reject,block" json:"reject,omitempty"`
+	Naming     *NamingSettings      `hcl:"naming,block" json:"naming,omitempty"`
+	Unused     *UnusedSettings      `hcl:"unused,block" json:"unused,omitempty"`
+	Tests      *TestsSettings       `hcl:"tests,block" json:"tests,omitempty"`
 }
 
 func (rule Rule) validate() (err error) {
@@ -73,6 +76,24 @@ func (rule Rule) validate() (err error) {
 		}
 	}
 
+	if rule.Naming != nil {
+		if err = rule.Naming.validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Unused != nil {
+		if err = rule.Unused.validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Tests != nil {
+		if err = rule.Tests.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -210,9 +231,46 @@ func (rule Rule) resolveChecks(ctx context.Context, path string, r parser.Rule,
 		}
 	}
 
+	if rule.Naming != nil {
+		enabled = append(enabled, checkMeta{
+			name:  checks.NamingCheckName,
+			check: checks.NewNamingCheck(rule.Naming.Level, rule.Naming.Operations, rule.Naming.getSeverity(checks.Warning)),
+		})
+	}
+
+	// pint ci only checks rules from modified files, so most recording rules
+	// would be reported as unused because rules using them are not checked
+	if rule.Unused != nil && !isCICommand(ctx) {
+		severity := rule.Unused.getSeverity(checks.Warning)
+		if len(prometheusServers) == 0 {
+			enabled = append(enabled, checkMeta{
+				name:  checks.RuleUnusedCheckName,
+				check: checks.NewRuleUnusedCheck(nil, rule.Unused.Sources, severity),
+			})
+		}
+		for _, prom := range prometheusServers {
+			enabled = append(enabled, checkMeta{
+				name:  checks.RuleUnusedCheckName,
+				check: checks.NewRuleUnusedCheck(prom, rule.Unused.Sources, severity),
+			})
+		}
+	}
+
+	if rule.Tests != nil {
+		enabled = append(enabled, checkMeta{
+			name:  checks.AlertsTestedCheckName,
+			check: checks.NewAlertsTestedCheck(rule.Tests.Files, rule.Tests.getSeverity(checks.Warning)),
+		})
+	}
+
 	return enabled
 }
 
+func isCICommand(ctx context.Context) bool {
+	cmd, ok := ctx.Value(CommandKey).(ContextCommandVal)
+	return ok && cmd == CICommand
+}
+
 func isEnabled(enabledChecks, disabledChecks []string, rule parser.Rule, name string, check checks.RuleChecker) bool {
 	instance := check.String()
 	comments := []string{
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type TestsSettings struct {
+	Files    []string `hcl:"files" json:"files"`
+	Severity string   `hcl:"severity,optional" json:"severity,omitempty"`
+}
+
+func (ts TestsSettings) validate() error {
+	if ts.Severity != "" {
+		if _, err := checks.ParseSeverity(ts.Severity); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range ts.Files {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts TestsSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ts.Severity != "" {
+		sev, _ := checks.ParseSeverity(ts.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestsSettings(t *testing.T) {
+	type testCaseT struct {
+		conf TestsSettings
+		err  error
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: TestsSettings{},
+		},
+		{
+			conf: TestsSettings{
+				Files:    []string{"tests/*.yml"},
+				Severity: "bug",
+			},
+		},
+		{
+			conf: TestsSettings{
+				Files: []string{"tests/[.yml"},
+			},
+			err: errors.New("syntax error in pattern"),
+		},
+		{
+			conf: TestsSettings{
+				Severity: "foo",
+			},
+			err: errors.New("unknown severity: foo"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			assert := assert.New(t)
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				assert.Equal(err, tc.err)
+			} else {
+				assert.EqualError(err, tc.err.Error())
+			}
+		})
+	}
+}
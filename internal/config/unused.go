@@ -0,0 +1,34 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type UnusedSettings struct {
+	Sources  []string `hcl:"sources,optional" json:"sources,omitempty"`
+	Severity string   `hcl:"severity,optional" json:"severity,omitempty"`
+}
+
+func (us UnusedSettings) validate() error {
+	if us.Severity != "" {
+		if _, err := checks.ParseSeverity(us.Severity); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range us.Sources {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (us UnusedSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if us.Severity != "" {
+		sev, _ := checks.ParseSeverity(us.Severity)
+		return sev
+	}
+	return fallback
+}
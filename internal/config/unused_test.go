@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnusedSettings(t *testing.T) {
+	type testCaseT struct {
+		conf UnusedSettings
+		err  error
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: UnusedSettings{},
+		},
+		{
+			conf: UnusedSettings{
+				Sources:  []string{"dashboards/*.json", "queries.txt"},
+				Severity: "info",
+			},
+		},
+		{
+			conf: UnusedSettings{
+				Sources: []string{"dashboards/[.json"},
+			},
+			err: errors.New("syntax error in pattern"),
+		},
+		{
+			conf: UnusedSettings{
+				Severity: "foo",
+			},
+			err: errors.New("unknown severity: foo"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			assert := assert.New(t)
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				assert.Equal(err, tc.err)
+			} else {
+				assert.EqualError(err, tc.err.Error())
+			}
+		})
+	}
+}
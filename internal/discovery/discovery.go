@@ -1,10 +1,14 @@
 package discovery
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"os"
 	"regexp"
 	"strings"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/rulefmt"
 	"gopkg.in/yaml.v3"
 
@@ -17,6 +21,9 @@ import (
 const (
 	FileOwnerComment = "file/owner"
 	RuleOwnerComment = "rule/owner"
+
+	prometheusRuleAPIGroup = "monitoring.coreos.com/"
+	prometheusRuleKind     = "PrometheusRule"
 )
 
 type RuleFinder interface {
@@ -28,10 +35,12 @@ type Entry struct {
 	PathError     error
 	ModifiedLines []int
 	Rule          parser.Rule
+	Group         *parser.RuleGroup
+	Document      int
 	Owner         string
 }
 
-func readFile(path string, isStrict bool) (entries []Entry, err error) {
+func readFile(path string, isStrict, isRuler bool) (entries []Entry, err error) {
 	p := parser.NewParser()
 
 	f, err := os.Open(path)
@@ -53,8 +62,12 @@ func readFile(path string, isStrict bool) (entries []Entry, err error) {
 	fileOwner, _ := parser.GetComment(string(content), FileOwnerComment)
 
 	if isStrict {
-		var r rulefmt.RuleGroups
-		if err = yaml.Unmarshal(content, &r); err != nil {
+		if isRuler {
+			err = validateRuler(content)
+		} else {
+			err = validateStrict(path, content)
+		}
+		if err != nil {
 			log.Error().
 				Err(err).
 				Str("path", path).
@@ -92,9 +105,11 @@ func readFile(path string, isStrict bool) (entries []Entry, err error) {
 			owner = fileOwner
 		}
 		entries = append(entries, Entry{
-			Path:  path,
-			Rule:  rule,
-			Owner: owner.Value,
+			Path:     path,
+			Rule:     rule,
+			Group:    rule.Group,
+			Document: rule.Document,
+			Owner:    owner.Value,
 		})
 	}
 
@@ -102,6 +117,151 @@ func readFile(path string, isStrict bool) (entries []Entry, err error) {
 	return entries, nil
 }
 
+// readDashboard returns an entry for every PromQL query found in given
+// Grafana dashboard file.
+func readDashboard(path string) (entries []Entry, err error) {
+	p := parser.NewParser()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := p.ParseDashboard(content)
+	if err != nil {
+		contentLines := []int{}
+		for i := 1; i <= strings.Count(string(content), "\n"); i++ {
+			contentLines = append(contentLines, i)
+		}
+		log.Error().
+			Err(err).
+			Str("path", path).
+			Str("lines", output.FormatLineRangeString(contentLines)).
+			Msg("Failed to parse dashboard file content")
+		entries = append(entries, Entry{
+			Path:          path,
+			PathError:     err,
+			ModifiedLines: contentLines,
+		})
+		return entries, nil
+	}
+
+	for _, rule := range rules {
+		entries = append(entries, Entry{
+			Path: path,
+			Rule: rule,
+		})
+	}
+
+	log.Info().Str("path", path).Int("queries", len(entries)).Msg("Dashboard parsed")
+	return entries, nil
+}
+
+// validateStrict checks that every YAML document in given content is either
+// a valid Prometheus rule file or a PrometheusRule object with valid groups
+// under spec.
+func validateStrict(path string, content []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if isPrometheusRule(&doc) {
+			log.Debug().Str("path", path).Int("line", doc.Line).Msg("Found PrometheusRule object")
+			var r struct {
+				Spec rulefmt.RuleGroups `yaml:"spec"`
+			}
+			if err = doc.Decode(&r); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var r rulefmt.RuleGroups
+		if err = doc.Decode(&r); err != nil {
+			return err
+		}
+	}
+}
+
+type rulerGroup struct {
+	Name                    string         `yaml:"name"`
+	Interval                model.Duration `yaml:"interval,omitempty"`
+	Limit                   int            `yaml:"limit,omitempty"`
+	PartialResponseStrategy string         `yaml:"partial_response_strategy,omitempty"`
+	SourceTenants           []string       `yaml:"source_tenants,omitempty"`
+	EvaluationDelay         model.Duration `yaml:"evaluation_delay,omitempty"`
+	QueryOffset             model.Duration `yaml:"query_offset,omitempty"`
+	Rules                   []rulerRule    `yaml:"rules"`
+}
+
+// rulerRule is a copy of rulefmt.RuleNode with fields that are only supported
+// by newer Prometheus versions.
+type rulerRule struct {
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           model.Duration    `yaml:"for,omitempty"`
+	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
+
+type rulerFile struct {
+	Namespace string       `yaml:"namespace"`
+	Groups    []rulerGroup `yaml:"groups"`
+}
+
+// validateRuler checks that every YAML document in given content is a valid
+// Cortex, Mimir or Thanos ruler namespace file. Unlike Prometheus rule files
+// any unknown key is reported.
+func validateRuler(content []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	for {
+		var r rulerFile
+		err := dec.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// isPrometheusRule returns true if given document is a Kubernetes
+// PrometheusRule object as defined by prometheus-operator.
+func isPrometheusRule(doc *yaml.Node) bool {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return false
+	}
+
+	var apiVersion, kind string
+	root := doc.Content[0]
+	for i := 0; i < len(root.Content)-1; i += 2 {
+		switch root.Content[i].Value {
+		case "apiVersion":
+			apiVersion = root.Content[i+1].Value
+		case "kind":
+			kind = root.Content[i+1].Value
+		}
+	}
+	return strings.HasPrefix(apiVersion, prometheusRuleAPIGroup) && kind == prometheusRuleKind
+}
+
 func matchesAny(re []*regexp.Regexp, s string) bool {
 	for _, r := range re {
 		if v := r.MatchString(s); v {
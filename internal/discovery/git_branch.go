@@ -16,6 +16,8 @@ func NewGitBranchFinder(
 	baseBranch string,
 	maxCommits int,
 	relaxed []*regexp.Regexp,
+	ruler []*regexp.Regexp,
+	dashboards []*regexp.Regexp,
 ) GitBranchFinder {
 	return GitBranchFinder{
 		gitCmd:     gitCmd,
@@ -23,6 +25,8 @@ func NewGitBranchFinder(
 		baseBranch: baseBranch,
 		maxCommits: maxCommits,
 		relaxed:    relaxed,
+		ruler:      ruler,
+		dashboards: dashboards,
 	}
 }
 
@@ -32,6 +36,8 @@ type GitBranchFinder struct {
 	baseBranch string
 	maxCommits int
 	relaxed    []*regexp.Regexp
+	ruler      []*regexp.Regexp
+	dashboards []*regexp.Regexp
 }
 
 func (f GitBranchFinder) Find() (entries []Entry, err error) {
@@ -121,7 +127,12 @@ func (f GitBranchFinder) Find() (entries []Entry, err error) {
 			allowedLines = append(allowedLines, lb.Line)
 		}
 
-		els, err := readFile(path, !matchesAny(f.relaxed, path))
+		var els []Entry
+		if matchesAny(f.dashboards, path) {
+			els, err = readDashboard(path)
+		} else {
+			els, err = readFile(path, !matchesAny(f.relaxed, path), matchesAny(f.ruler, path))
+		}
 		if err != nil {
 			return nil, err
 		}
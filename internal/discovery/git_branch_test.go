@@ -90,6 +90,8 @@ func TestGitBranchFinder(t *testing.T) {
 				"main",
 				0,
 				nil,
+				nil,
+				nil,
 			),
 			err: "failed to get the list of commits to scan: mock error",
 		},
@@ -108,6 +110,8 @@ func TestGitBranchFinder(t *testing.T) {
 				"main",
 				0,
 				nil,
+				nil,
+				nil,
 			),
 			err: "failed to get the list of modified files from git: mock error",
 		},
@@ -132,6 +136,8 @@ func TestGitBranchFinder(t *testing.T) {
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			err: "failed to get commit message for commit1: mock error",
 		},
@@ -158,6 +164,8 @@ func TestGitBranchFinder(t *testing.T) {
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			err: "failed to run git blame for foo.yml: mock error",
 		},
@@ -189,6 +197,8 @@ func TestGitBranchFinder(t *testing.T) {
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			err: "open foo.yml: no such file or directory",
 		},
@@ -222,6 +232,8 @@ func TestGitBranchFinder(t *testing.T) {
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			rules: []rule{
 				{path: "foo.yml", name: "first", lines: []int{2, 3}, modified: []int{2}},
@@ -334,6 +346,8 @@ R090    foo/c2c.yml         c2c.yml
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			rules: []rule{
 				{path: "c3b.yml", name: "first", lines: []int{2, 3}, modified: []int{2, 3}},
@@ -386,6 +400,8 @@ R090    foo/c2c.yml         c2c.yml
 				"main",
 				0,
 				nil,
+				nil,
+				nil,
 			),
 			rules: []rule{
 				{path: "foo.yml", modified: []int{2, 7, 8}},
@@ -414,6 +430,8 @@ R090    foo/c2c.yml         c2c.yml
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			rules: nil,
 		},
@@ -440,6 +458,8 @@ R090    foo/c2c.yml         c2c.yml
 				"main",
 				0,
 				[]*regexp.Regexp{regexp.MustCompile(".*")},
+				nil,
+				nil,
 			),
 			rules: nil,
 		},
@@ -8,16 +8,20 @@ import (
 	"regexp"
 )
 
-func NewGlobFinder(patterns []string, relaxed []*regexp.Regexp) GlobFinder {
+func NewGlobFinder(patterns []string, relaxed, ruler, dashboards []*regexp.Regexp) GlobFinder {
 	return GlobFinder{
-		patterns: patterns,
-		relaxed:  relaxed,
+		patterns:   patterns,
+		relaxed:    relaxed,
+		ruler:      ruler,
+		dashboards: dashboards,
 	}
 }
 
 type GlobFinder struct {
-	patterns []string
-	relaxed  []*regexp.Regexp
+	patterns   []string
+	relaxed    []*regexp.Regexp
+	ruler      []*regexp.Regexp
+	dashboards []*regexp.Regexp
 }
 
 func (f GlobFinder) Find() (entries []Entry, err error) {
@@ -50,7 +54,12 @@ func (f GlobFinder) Find() (entries []Entry, err error) {
 	}
 
 	for _, path := range paths {
-		el, err := readFile(path, !matchesAny(f.relaxed, path))
+		var el []Entry
+		if matchesAny(f.dashboards, path) {
+			el, err = readDashboard(path)
+		} else {
+			el, err = readFile(path, !matchesAny(f.relaxed, path), matchesAny(f.ruler, path))
+		}
 		if err != nil {
 			return nil, fmt.Errorf("invalid file syntax: %w", err)
 		}
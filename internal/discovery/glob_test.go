@@ -36,35 +36,114 @@ func TestGlobPathFinder(t *testing.T) {
 	var r rulefmt.RuleGroups
 	strictErr := yaml.Unmarshal([]byte(testRuleBody), &r)
 
+	groupRuleBody := "# pint file/owner bob\n\ngroups:\n- name: foo\n  interval: 1m\n  rules:\n  - record: foo\n    expr: sum(foo)\n"
+	groupRules, err := p.Parse([]byte(groupRuleBody))
+	require.NoError(t, err)
+
+	crdRuleBody := `# pint file/owner bob
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups:
+  - name: foo
+    rules:
+    - record: foo
+      expr: sum(foo)
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: bar
+spec:
+  groups:
+  - name: bar
+    rules:
+    - alert: bar
+      expr: sum(bar) == 0
+`
+	crdRules, err := p.Parse([]byte(crdRuleBody))
+	require.NoError(t, err)
+	require.Len(t, crdRules, 2)
+
+	crdInvalidBody := `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups:
+  - name: foo
+    rules:
+      record: foo
+      expr: sum(foo)
+`
+	multiDocBody := "groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n---\ngroups:\n- name: bar\n  rules:\n  - record: bar\n    expr: sum(bar)\n"
+	multiDocRules, err := p.Parse([]byte(multiDocBody))
+	require.NoError(t, err)
+	require.Len(t, multiDocRules, 2)
+
+	multiDocInvalidBody := "groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(foo)\n---\n- record: bar\n  expr: sum(bar)\n"
+	multiDocErr := yaml.Unmarshal([]byte("\n\n\n\n\n\n- record: bar\n  expr: sum(bar)\n"), &r)
+	require.Error(t, multiDocErr)
+
+	rulerBody := "namespace: foo\ngroups:\n- name: foo\n  source_tenants: [a, b]\n  evaluation_delay: 1m\n  rules:\n  - record: foo\n    expr: sum(foo)\n"
+	rulerRules, err := p.Parse([]byte(rulerBody))
+	require.NoError(t, err)
+	require.Len(t, rulerRules, 1)
+
+	rulerInvalidBody := "namespace: foo\ngroups:\n- name: foo\n  evaluation_delay: 1x\n  rules:\n  - record: foo\n    expr: sum(foo)\n"
+	rulerUnknownBody := "namespace: foo\ngroups:\n- name: foo\n  tenants: [a, b]\n  rules:\n  - record: foo\n    expr: sum(foo)\n"
+	rulerUnknownRules, err := p.Parse([]byte(rulerUnknownBody))
+	require.NoError(t, err)
+	require.Len(t, rulerUnknownRules, 1)
+
+	rulerKeepFiringBody := "namespace: foo\ngroups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: up == 0\n    keep_firing_for: 5m\n"
+	rulerKeepFiringRules, err := p.Parse([]byte(rulerKeepFiringBody))
+	require.NoError(t, err)
+	require.Len(t, rulerKeepFiringRules, 1)
+
+	dashboardBody := "{\n  \"panels\": [\n    {\"targets\": [{\"expr\": \"sum(foo)\"}]}\n  ]\n}\n"
+	dashboardRules, err := p.ParseDashboard([]byte(dashboardBody))
+	require.NoError(t, err)
+	require.Len(t, dashboardRules, 1)
+
+	var crd struct {
+		Spec rulefmt.RuleGroups `yaml:"spec"`
+	}
+	crdErr := yaml.Unmarshal([]byte(crdInvalidBody), &crd)
+	require.Error(t, crdErr)
+
 	testCases := []testCaseT{
 		{
 			files:  map[string]string{},
-			finder: discovery.NewGlobFinder([]string{"[]"}, nil),
+			finder: discovery.NewGlobFinder([]string{"[]"}, nil, nil, nil),
 			err:    filepath.ErrBadPattern,
 		},
 		{
 			files:  map[string]string{},
-			finder: discovery.NewGlobFinder([]string{"*"}, nil),
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
 			err:    fmt.Errorf("no matching files"),
 		},
 		{
 			files:  map[string]string{},
-			finder: discovery.NewGlobFinder([]string{"*"}, nil),
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
 			err:    fmt.Errorf("no matching files"),
 		},
 		{
 			files:  map[string]string{},
-			finder: discovery.NewGlobFinder([]string{"foo/*"}, nil),
+			finder: discovery.NewGlobFinder([]string{"foo/*"}, nil, nil, nil),
 			err:    fmt.Errorf("no matching files"),
 		},
 		{
 			files:  map[string]string{"bar.yml": testRuleBody},
-			finder: discovery.NewGlobFinder([]string{"foo/*"}, nil),
+			finder: discovery.NewGlobFinder([]string{"foo/*"}, nil, nil, nil),
 			err:    fmt.Errorf("no matching files"),
 		},
 		{
 			files:  map[string]string{"bar.yml": testRuleBody},
-			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}),
+			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}, nil, nil),
 			entries: []discovery.Entry{
 				{
 					Path:          "bar.yml",
@@ -76,7 +155,7 @@ func TestGlobPathFinder(t *testing.T) {
 		},
 		{
 			files:  map[string]string{"foo/bar.yml": testRuleBody + "\n\n# pint file/owner alice\n"},
-			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}),
+			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}, nil, nil),
 			entries: []discovery.Entry{
 				{
 					Path:          "foo/bar.yml",
@@ -86,9 +165,22 @@ func TestGlobPathFinder(t *testing.T) {
 				},
 			},
 		},
+		{
+			files:  map[string]string{"bar.yml": groupRuleBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          groupRules[0],
+					Group:         groupRules[0].Group,
+					ModifiedLines: groupRules[0].Lines(),
+					Owner:         "bob",
+				},
+			},
+		},
 		{
 			files:  map[string]string{"bar.yml": testRuleBody},
-			finder: discovery.NewGlobFinder([]string{"*"}, nil),
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
 			entries: []discovery.Entry{
 				{
 					Path:          "bar.yml",
@@ -98,9 +190,129 @@ func TestGlobPathFinder(t *testing.T) {
 				},
 			},
 		},
+		{
+			files:  map[string]string{"bar.yml": multiDocBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          multiDocRules[0],
+					Group:         multiDocRules[0].Group,
+					ModifiedLines: []int{4, 5},
+				},
+				{
+					Path:          "bar.yml",
+					Rule:          multiDocRules[1],
+					Group:         multiDocRules[1].Group,
+					Document:      1,
+					ModifiedLines: []int{10, 11},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": multiDocInvalidBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					PathError:     multiDocErr,
+					ModifiedLines: []int{1, 2, 3, 4, 5, 6, 7, 8},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": rulerBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, []*regexp.Regexp{regexp.MustCompile(".*")}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          rulerRules[0],
+					Group:         rulerRules[0].Group,
+					ModifiedLines: []int{7, 8},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": rulerInvalidBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, []*regexp.Regexp{regexp.MustCompile(".*")}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					PathError:     errors.New(`not a valid duration string: "1x"`),
+					ModifiedLines: []int{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": rulerUnknownBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, []*regexp.Regexp{regexp.MustCompile(".*")}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					PathError:     &yaml.TypeError{Errors: []string{"line 4: field tenants not found in type discovery.rulerGroup"}},
+					ModifiedLines: []int{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": rulerUnknownBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}, []*regexp.Regexp{regexp.MustCompile(".*")}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          rulerUnknownRules[0],
+					Group:         rulerUnknownRules[0].Group,
+					ModifiedLines: []int{6, 7},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": rulerKeepFiringBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, []*regexp.Regexp{regexp.MustCompile(".*")}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          rulerKeepFiringRules[0],
+					Group:         rulerKeepFiringRules[0].Group,
+					ModifiedLines: []int{5, 6, 7},
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": crdRuleBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          crdRules[0],
+					Group:         crdRules[0].Group,
+					ModifiedLines: []int{11, 12},
+					Owner:         "bob",
+				},
+				{
+					Path:          "bar.yml",
+					Rule:          crdRules[1],
+					Group:         crdRules[1].Group,
+					Document:      1,
+					ModifiedLines: []int{22, 23},
+					Owner:         "bob",
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": crdInvalidBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					PathError:     crdErr,
+					ModifiedLines: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+				},
+			},
+		},
 		{
 			files:  map[string]string{"bar.yml": "record:::{}\n  expr: sum(foo)\n\n# pint file/owner bob\n"},
-			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}),
+			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}, nil, nil),
 			entries: []discovery.Entry{
 				{
 					Path:          "bar.yml",
@@ -110,6 +322,37 @@ func TestGlobPathFinder(t *testing.T) {
 				},
 			},
 		},
+		{
+			files: map[string]string{
+				"dashboards/foo.json": dashboardBody,
+				"rules.yml":           testRuleBody,
+			},
+			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}, nil, []*regexp.Regexp{regexp.MustCompile("dashboards/.+[.]json")}),
+			entries: []discovery.Entry{
+				{
+					Path:          "dashboards/foo.json",
+					Rule:          dashboardRules[0],
+					ModifiedLines: []int{3},
+				},
+				{
+					Path:          "rules.yml",
+					Rule:          testRules[0],
+					ModifiedLines: []int{3, 4},
+					Owner:         "bob",
+				},
+			},
+		},
+		{
+			files:  map[string]string{"foo.json": "{\n"},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil, nil, []*regexp.Regexp{regexp.MustCompile(".+[.]json")}),
+			entries: []discovery.Entry{
+				{
+					Path:          "foo.json",
+					PathError:     errors.New("yaml: line 1: did not find expected node content"),
+					ModifiedLines: []int{1},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
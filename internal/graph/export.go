@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	AlertingNode  = "alerting"
+	RecordingNode = "recording"
+	MetricNode    = "metric"
+)
+
+// Export is a serializable representation of a graph.
+type Export struct {
+	Nodes []ExportNode `json:"nodes"`
+	Edges []ExportEdge `json:"edges"`
+}
+
+type ExportNode struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Path  string `json:"path,omitempty"`
+	Line  int    `json:"line,omitempty"`
+	Owner string `json:"owner,omitempty"`
+}
+
+type ExportEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NewExport returns an export of given nodes, with edges to their recording
+// rule dependencies and to all raw metrics they use.
+// Dependencies not present in nodes are skipped.
+func NewExport(nodes []*Node) Export {
+	e := Export{Nodes: []ExportNode{}, Edges: []ExportEdge{}}
+
+	included := map[*Node]bool{}
+	for _, node := range nodes {
+		included[node] = true
+	}
+
+	metrics := map[string]bool{}
+	edges := map[ExportEdge]bool{}
+	addEdge := func(edge ExportEdge) {
+		if !edges[edge] {
+			edges[edge] = true
+			e.Edges = append(e.Edges, edge)
+		}
+	}
+
+	for _, node := range nodes {
+		e.Nodes = append(e.Nodes, ExportNode{
+			ID:    node.ID(),
+			Type:  node.Type(),
+			Name:  node.Name(),
+			Path:  node.Path,
+			Line:  node.Rule.Lines()[0],
+			Owner: node.Owner,
+		})
+		for _, dep := range node.Dependencies {
+			if included[dep.Node] {
+				addEdge(ExportEdge{From: node.ID(), To: dep.Node.ID()})
+			}
+		}
+		for _, m := range node.Metrics {
+			metrics[m.Name] = true
+			addEdge(ExportEdge{From: node.ID(), To: m.Name})
+		}
+	}
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e.Nodes = append(e.Nodes, ExportNode{ID: name, Type: MetricNode, Name: name})
+	}
+
+	return e
+}
+
+// DOT returns the graph in Graphviz DOT format.
+func (e Export) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph pint {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range e.Nodes {
+		var shape string
+		switch node.Type {
+		case AlertingNode:
+			shape = "box"
+		case RecordingNode:
+			shape = "ellipse"
+		default:
+			shape = "plaintext"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", strconv.Quote(node.ID), strconv.Quote(node.Name), shape)
+	}
+	for _, edge := range e.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
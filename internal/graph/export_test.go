@@ -0,0 +1,49 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/graph"
+)
+
+func TestExport(t *testing.T) {
+	entries := mustParseEntries(t, "rules.yml", `
+- record: job:up:sum
+  expr: sum(up) by(job)
+- alert: JobDown
+  expr: job:up:sum == 0 or absent(up{job="foo"})
+`)
+	entries[1].Owner = "bob"
+	g := graph.New(entries)
+
+	e := graph.NewExport(g.Nodes)
+	require.Equal(t, graph.Export{
+		Nodes: []graph.ExportNode{
+			{ID: "rules.yml:2", Type: graph.RecordingNode, Name: "job:up:sum", Path: "rules.yml", Line: 2},
+			{ID: "rules.yml:4", Type: graph.AlertingNode, Name: "JobDown", Path: "rules.yml", Line: 4, Owner: "bob"},
+			{ID: "up", Type: graph.MetricNode, Name: "up"},
+		},
+		Edges: []graph.ExportEdge{
+			{From: "rules.yml:2", To: "up"},
+			{From: "rules.yml:4", To: "rules.yml:2"},
+			{From: "rules.yml:4", To: "up"},
+		},
+	}, e)
+
+	require.Equal(t, `digraph pint {
+  rankdir=LR;
+  "rules.yml:2" [label="job:up:sum", shape=ellipse];
+  "rules.yml:4" [label="JobDown", shape=box];
+  "up" [label="up", shape=plaintext];
+  "rules.yml:2" -> "up";
+  "rules.yml:4" -> "rules.yml:2";
+  "rules.yml:4" -> "up";
+}
+`, e.DOT())
+
+	// dependencies outside of exported nodes are skipped
+	e = graph.NewExport(g.Nodes[1:])
+	require.Equal(t, []graph.ExportEdge{{From: "rules.yml:4", To: "up"}}, e.Edges)
+}
@@ -0,0 +1,283 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// Node is a single alerting or recording rule.
+type Node struct {
+	Path  string
+	Owner string
+	Rule  parser.Rule
+	// Dependencies is the list of recording rules used by this rule.
+	Dependencies []Dependency
+	// Metrics is the list of raw metrics used by this rule, these are
+	// all metrics that are not produced by any recording rule.
+	Metrics []Metric
+}
+
+// ID returns a unique identifier of this rule.
+func (n *Node) ID() string {
+	return fmt.Sprintf("%s:%d", n.Path, n.Rule.Lines()[0])
+}
+
+// Type returns the rule type, either alerting or recording.
+func (n *Node) Type() string {
+	if n.Rule.RecordingRule != nil {
+		return RecordingNode
+	}
+	return AlertingNode
+}
+
+// Name returns the record or alert name of this rule.
+func (n *Node) Name() string {
+	if n.Rule.RecordingRule != nil {
+		return n.Rule.RecordingRule.Record.Value.Value
+	}
+	return n.Rule.AlertingRule.Alert.Value.Value
+}
+
+// SameGroup returns true if both nodes are defined in the same group.
+func (n *Node) SameGroup(o *Node) bool {
+	if n.Rule.Group == nil || o.Rule.Group == nil {
+		return false
+	}
+	return n.Path == o.Path &&
+		n.Rule.Document == o.Rule.Document &&
+		n.Rule.Group.Name.Value.Value == o.Rule.Group.Name.Value.Value &&
+		n.Rule.Group.Name.Value.Position.FirstLine() == o.Rule.Group.Name.Value.Position.FirstLine()
+}
+
+// Dependency is a reference from a rule query to a recording rule.
+type Dependency struct {
+	Node     *Node
+	Selector *promParser.VectorSelector
+}
+
+// Metric is a reference from a rule query to a raw metric.
+type Metric struct {
+	Name     string
+	Selector *promParser.VectorSelector
+}
+
+// Graph holds all rules and references between them.
+type Graph struct {
+	Nodes []*Node
+
+	alerts     map[*parser.AlertingRule]*Node
+	records    map[*parser.RecordingRule]*Node
+	dependents map[*Node][]*Node
+}
+
+type contextKey struct{}
+
+type lazyGraph struct {
+	once    sync.Once
+	entries []discovery.Entry
+	graph   *Graph
+}
+
+// NewContext returns a copy of ctx that will build a graph from given entries
+// the first time it's needed. Checks run with that context will all use the
+// same graph instead of building one for every rule.
+func NewContext(ctx context.Context, entries []discovery.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, &lazyGraph{entries: entries})
+}
+
+// FromContext returns the graph stored in ctx by NewContext, or a new graph
+// built from given entries if there's none.
+func FromContext(ctx context.Context, entries []discovery.Entry) *Graph {
+	lg, ok := ctx.Value(contextKey{}).(*lazyGraph)
+	if !ok {
+		return New(entries)
+	}
+	lg.once.Do(func() {
+		lg.graph = New(lg.entries)
+	})
+	return lg.graph
+}
+
+// New builds a graph from all valid rules found in given entries.
+// Every rule will depend on all recording rules with a name and static
+// labels matching any of the vector selectors used in its query.
+func New(entries []discovery.Entry) *Graph {
+	g := Graph{
+		alerts:     map[*parser.AlertingRule]*Node{},
+		records:    map[*parser.RecordingRule]*Node{},
+		dependents: map[*Node][]*Node{},
+	}
+
+	records := map[string][]*Node{}
+	for _, entry := range entries {
+		if entry.PathError != nil || entry.Rule.Error.Err != nil {
+			continue
+		}
+		if entry.Rule.AlertingRule == nil && entry.Rule.RecordingRule == nil {
+			continue
+		}
+		node := &Node{Path: entry.Path, Owner: entry.Owner, Rule: entry.Rule}
+		g.Nodes = append(g.Nodes, node)
+		if entry.Rule.AlertingRule != nil {
+			g.alerts[entry.Rule.AlertingRule] = node
+		}
+		if entry.Rule.RecordingRule != nil {
+			g.records[entry.Rule.RecordingRule] = node
+			records[node.Name()] = append(records[node.Name()], node)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		expr := node.Rule.Expr()
+		if expr.SyntaxError != nil || expr.Query == nil {
+			continue
+		}
+		for _, vs := range selectors(expr.Query.Node) {
+			for _, name := range metricNames(vs) {
+				if _, ok := records[name]; !ok {
+					node.Metrics = append(node.Metrics, Metric{Name: name, Selector: vs})
+					continue
+				}
+				for _, dep := range records[name] {
+					if matchesStaticLabels(vs, dep.Rule) {
+						node.Dependencies = append(node.Dependencies, Dependency{Node: dep, Selector: vs})
+					}
+				}
+			}
+		}
+		for _, dep := range node.Dependencies {
+			if deps := g.dependents[dep.Node]; len(deps) == 0 || deps[len(deps)-1] != node {
+				g.dependents[dep.Node] = append(deps, node)
+			}
+		}
+	}
+
+	return &g
+}
+
+// Node returns the graph node for given rule or nil if it's not in the graph.
+func (g *Graph) Node(rule parser.Rule) *Node {
+	if rule.AlertingRule != nil {
+		return g.alerts[rule.AlertingRule]
+	}
+	if rule.RecordingRule != nil {
+		return g.records[rule.RecordingRule]
+	}
+	return nil
+}
+
+// Dependents returns all rules using given recording rule.
+func (g *Graph) Dependents(node *Node) (nodes []*Node) {
+	return g.dependents[node]
+}
+
+// Related returns all nodes matching given function together with all rules
+// they depend on and all rules depending on them. Nodes are returned in the
+// same order as they appear in the graph.
+func (g *Graph) Related(match func(*Node) bool) (nodes []*Node) {
+	related := map[*Node]bool{}
+	walk := func(next func(*Node) []*Node) {
+		visited := map[*Node]bool{}
+		var visit func(n *Node)
+		visit = func(n *Node) {
+			if visited[n] {
+				return
+			}
+			visited[n] = true
+			related[n] = true
+			for _, o := range next(n) {
+				visit(o)
+			}
+		}
+		for _, node := range g.Nodes {
+			if match(node) {
+				visit(node)
+			}
+		}
+	}
+
+	walk(func(n *Node) (deps []*Node) {
+		for _, dep := range n.Dependencies {
+			deps = append(deps, dep.Node)
+		}
+		return deps
+	})
+	walk(g.Dependents)
+
+	for _, node := range g.Nodes {
+		if related[node] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Cycle returns a list of nodes forming a dependency loop that starts and ends
+// on given node, or nil if there's no such loop.
+func (g *Graph) Cycle(node *Node) []*Node {
+	visited := map[*Node]bool{}
+	var walk func(n *Node, path []*Node) []*Node
+	walk = func(n *Node, path []*Node) []*Node {
+		for _, dep := range n.Dependencies {
+			if dep.Node == node {
+				return append(path, node)
+			}
+			if visited[dep.Node] {
+				continue
+			}
+			visited[dep.Node] = true
+			if cycle := walk(dep.Node, append(path, dep.Node)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(node, []*Node{node})
+}
+
+func selectors(node promParser.Node) (vs []*promParser.VectorSelector) {
+	promParser.Inspect(node, func(n promParser.Node, _ []promParser.Node) error {
+		if s, ok := n.(*promParser.VectorSelector); ok {
+			vs = append(vs, s)
+		}
+		return nil
+	})
+	return vs
+}
+
+// metricNames returns all metric names used by given selector. Selectors
+// without a name or with a regexp name matcher will return no names.
+func metricNames(vs *promParser.VectorSelector) (names []string) {
+	if vs.Name != "" {
+		return []string{vs.Name}
+	}
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name == labels.MetricName && lm.Type == labels.MatchEqual {
+			names = append(names, lm.Value)
+		}
+	}
+	return names
+}
+
+// matchesStaticLabels returns false if the selector is filtering on a label
+// that the recording rule sets to a value that doesn't match.
+func matchesStaticLabels(vs *promParser.VectorSelector, rule parser.Rule) bool {
+	if rule.RecordingRule.Labels == nil {
+		return true
+	}
+	for _, lm := range vs.LabelMatchers {
+		for _, label := range rule.RecordingRule.Labels.Items {
+			if label.Key.Value == lm.Name && !lm.Matches(label.Value.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+package graph_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func mustParseEntries(t *testing.T, path, content string) (entries []discovery.Entry) {
+	p := parser.NewParser()
+	rules, err := p.Parse([]byte(content))
+	require.NoError(t, err)
+	for _, rule := range rules {
+		entries = append(entries, discovery.Entry{
+			Path:          path,
+			ModifiedLines: rule.Lines(),
+			Rule:          rule,
+		})
+	}
+	return entries
+}
+
+func dependencyNames(node *graph.Node) (names []string) {
+	for _, dep := range node.Dependencies {
+		names = append(names, dep.Node.Name())
+	}
+	return names
+}
+
+func TestGraph(t *testing.T) {
+	type testCaseT struct {
+		content      string
+		dependencies map[string][]string
+	}
+
+	testCases := []testCaseT{
+		{
+			content: `
+- record: foo
+  expr: sum(up)
+- alert: bar
+  expr: foo == 0
+`,
+			dependencies: map[string][]string{
+				"foo": nil,
+				"bar": {"foo"},
+			},
+		},
+		{
+			content: `
+- record: foo
+  expr: sum(up)
+- record: foo
+  expr: count(up)
+  labels:
+    env: dev
+- alert: bar
+  expr: foo{env="prod"} == 0
+- alert: baz
+  expr: foo{env=~"dev|prod"} == 0 or {__name__="foo"} > 1
+`,
+			dependencies: map[string][]string{
+				"foo": nil,
+				"bar": {"foo"},
+				"baz": {"foo", "foo", "foo", "foo"},
+			},
+		},
+		{
+			content: `
+- record: foo
+  expr: sum(bar)
+- record: bar
+  expr: sum({__name__=~"foo|bar"})
+- alert: baz
+  expr: sum(foo) without(
+`,
+			dependencies: map[string][]string{
+				"foo": {"bar"},
+				"bar": nil,
+				"baz": nil,
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			g := graph.New(mustParseEntries(t, "rules.yml", tc.content))
+			for _, node := range g.Nodes {
+				require.Equal(t, tc.dependencies[node.Name()], dependencyNames(node), "wrong dependencies for %s", node.Name())
+			}
+		})
+	}
+}
+
+func TestGraphCycle(t *testing.T) {
+	entries := mustParseEntries(t, "rules.yml", `
+- record: a
+  expr: sum(b)
+- record: b
+  expr: sum(c)
+- record: c
+  expr: sum(a)
+- record: d
+  expr: sum(a) + d
+- record: e
+  expr: sum(d)
+`)
+	g := graph.New(entries)
+
+	cycleNames := func(rule parser.Rule) (names []string) {
+		node := g.Node(rule)
+		require.NotNil(t, node)
+		for _, n := range g.Cycle(node) {
+			names = append(names, n.Name())
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"a", "b", "c", "a"}, cycleNames(entries[0].Rule))
+	require.Equal(t, []string{"b", "c", "a", "b"}, cycleNames(entries[1].Rule))
+	require.Equal(t, []string{"c", "a", "b", "c"}, cycleNames(entries[2].Rule))
+	require.Equal(t, []string{"d", "d"}, cycleNames(entries[3].Rule))
+	require.Nil(t, cycleNames(entries[4].Rule))
+}
+
+func TestGraphSameGroup(t *testing.T) {
+	entries := mustParseEntries(t, "rules.yml", `
+groups:
+- name: foo
+  rules:
+  - record: a
+    expr: sum(up)
+  - record: b
+    expr: sum(up)
+- name: bar
+  rules:
+  - record: c
+    expr: sum(up)
+`)
+	other := mustParseEntries(t, "other.yml", `
+groups:
+- name: foo
+  rules:
+  - record: a
+    expr: sum(up)
+`)
+	g := graph.New(append(entries, other...))
+	require.Len(t, g.Nodes, 4)
+	require.True(t, g.Nodes[0].SameGroup(g.Nodes[1]))
+	require.False(t, g.Nodes[0].SameGroup(g.Nodes[2]))
+	require.False(t, g.Nodes[0].SameGroup(g.Nodes[3]))
+}
+
+func TestGraphMetrics(t *testing.T) {
+	entries := mustParseEntries(t, "rules.yml", `
+- record: foo
+  expr: sum(up) + sum(bar{job="foo"})
+- alert: bar
+  expr: foo > 0 and on() {__name__="up"} and {job="foo"}
+`)
+	g := graph.New(entries)
+
+	metricNames := func(node *graph.Node) (names []string) {
+		for _, m := range node.Metrics {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+
+	require.Len(t, g.Nodes, 2)
+	require.Equal(t, []string{"up", "bar"}, metricNames(g.Nodes[0]))
+	require.Equal(t, []string{"up"}, metricNames(g.Nodes[1]))
+}
+
+func TestGraphRelated(t *testing.T) {
+	entries := mustParseEntries(t, "rules.yml", `
+- record: a
+  expr: sum(up)
+- record: b
+  expr: sum(a)
+- record: c
+  expr: sum(a)
+- alert: d
+  expr: b > 0
+- alert: e
+  expr: up == 0
+`)
+	g := graph.New(entries)
+
+	related := func(name string) (names []string) {
+		for _, n := range g.Related(func(n *graph.Node) bool { return n.Name() == name }) {
+			names = append(names, n.Name())
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"a", "b", "c", "d"}, related("a"))
+	require.Equal(t, []string{"a", "b", "d"}, related("b"))
+	require.Equal(t, []string{"a", "c"}, related("c"))
+	require.Equal(t, []string{"a", "b", "d"}, related("d"))
+	require.Equal(t, []string{"e"}, related("e"))
+	require.Nil(t, related("f"))
+}
+
+func TestGraphFromContext(t *testing.T) {
+	entries := mustParseEntries(t, "rules.yml", `
+- record: foo
+  expr: sum(up)
+- alert: bar
+  expr: foo == 0
+`)
+	ctx := graph.NewContext(context.Background(), entries)
+	g := graph.FromContext(ctx, entries)
+	require.Len(t, g.Nodes, 2)
+	require.Same(t, g, graph.FromContext(ctx, entries), "same context should return the same graph")
+	require.Equal(t, []*graph.Node{g.Node(entries[1].Rule)}, g.Dependents(g.Node(entries[0].Rule)))
+
+	// modified entries stored in the same slice are used by the next run
+	other := mustParseEntries(t, "rules.yml", `
+- record: foo
+  expr: sum(up)
+- record: bar
+  expr: sum(foo)
+`)
+	copy(entries, other)
+	og := graph.FromContext(graph.NewContext(context.Background(), entries), entries)
+	require.NotSame(t, g, og, "new context should return a new graph")
+	require.Len(t, og.Nodes, 2)
+	require.Equal(t, "bar", og.Nodes[1].Name())
+
+	require.NotSame(t, graph.FromContext(context.Background(), entries), graph.FromContext(context.Background(), entries),
+		"graph is built for every call without a context")
+}
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dashboardPanelsKey     = "panels"
+	dashboardRowsKey       = "rows"
+	dashboardTargetsKey    = "targets"
+	dashboardDatasourceKey = "datasource"
+	dashboardTemplatingKey = "templating"
+	dashboardListKey       = "list"
+	dashboardDashboardKey  = "dashboard"
+
+	prometheusDatasourceType = "prometheus"
+)
+
+// DashboardQuery is a single PromQL query from a Grafana dashboard panel.
+type DashboardQuery struct {
+	// JSON path of the expr key, for example panels[2].targets[0].expr
+	Path string
+	Expr PromQLExpr
+}
+
+func (dq DashboardQuery) Lines() []int {
+	return dq.Expr.Lines()
+}
+
+// Values used for Grafana global variables, these are only used to make
+// queries valid PromQL so any sensible duration will work.
+var dashboardGlobalVariables = map[string]string{
+	"__rate_interval": "5m",
+	"__interval":      "1m",
+	"__interval_ms":   "60000",
+	"__range":         "1h",
+	"__range_s":       "3600",
+	"__range_ms":      "3600000",
+}
+
+var dashboardVariableRe = regexp.MustCompile(`\$\{(\w+)(?::[^}]+)?\}|\[\[(\w+)(?::[^\]]+)?\]\]|\$(\w+)`)
+
+// ParseDashboard returns all PromQL queries from a Grafana dashboard.
+// Dashboards are stored as JSON, which is a subset of YAML, so it's decoded
+// as YAML to keep track of line numbers.
+func (p Parser) ParseDashboard(content []byte) (rules []Rule, err error) {
+	if len(content) == 0 {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unable to parse dashboard JSON file: %s", r)
+		}
+	}()
+
+	var doc yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	if err = dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("dashboard must be a JSON object")
+	}
+
+	root := doc.Content[0]
+	path := ""
+	// dashboards exported via Grafana API are wrapped in another object
+	if d := mappingValue(root, dashboardDashboardKey); d != nil && d.Kind == yaml.MappingNode {
+		root = d
+		path = dashboardDashboardKey + "."
+	}
+
+	vars := dashboardVariables(root)
+	for _, key := range []string{dashboardPanelsKey, dashboardRowsKey} {
+		rules = append(rules, parsePanels(content, mappingValue(root, key), path+key, vars)...)
+	}
+	return rules, nil
+}
+
+func parsePanels(content []byte, panels *yaml.Node, path string, vars map[string]string) (rules []Rule) {
+	if panels == nil || panels.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for i, panel := range panels.Content {
+		if panel.Kind != yaml.MappingNode {
+			continue
+		}
+		panelPath := fmt.Sprintf("%s[%d]", path, i)
+
+		if targets := mappingValue(panel, dashboardTargetsKey); targets != nil && targets.Kind == yaml.SequenceNode {
+			for j, target := range targets.Content {
+				if target.Kind != yaml.MappingNode || !isPrometheusDatasource(panel, target) {
+					continue
+				}
+				key, val := MappingKeyValue(target, exprKey)
+				if key == nil || val.Kind != yaml.ScalarNode || strings.TrimSpace(val.Value) == "" {
+					continue
+				}
+				rules = append(rules, Rule{
+					DashboardQuery: newDashboardQuery(content, key, val, fmt.Sprintf("%s.%s[%d].%s", panelPath, dashboardTargetsKey, j, exprKey), vars),
+				})
+			}
+		}
+
+		// rows can have nested panels
+		rules = append(rules, parsePanels(content, mappingValue(panel, dashboardPanelsKey), panelPath+"."+dashboardPanelsKey, vars)...)
+	}
+
+	return rules
+}
+
+func newDashboardQuery(content []byte, key, val *yaml.Node, path string, vars map[string]string) *DashboardQuery {
+	expanded := expandDashboardVariables(val.Value, vars)
+	node := *val
+	node.Value = expanded
+	expr := newPromQLExpr(content, key, &node, 0)
+	// JSON strings are always on a single line, multi-line queries
+	// use escaped line breaks
+	expr.Key.Position = NewFilePosition([]int{key.Line})
+	expr.Value.Position = NewFilePosition([]int{val.Line})
+	if expanded != val.Value {
+		expr.positions = nil
+	}
+	return &DashboardQuery{Path: path, Expr: *expr}
+}
+
+// dashboardVariables returns the current value of all template variables
+// defined on the dashboard.
+func dashboardVariables(root *yaml.Node) map[string]string {
+	vars := map[string]string{}
+	for k, v := range dashboardGlobalVariables {
+		vars[k] = v
+	}
+
+	templating := mappingValue(root, dashboardTemplatingKey)
+	if templating == nil {
+		return vars
+	}
+	list := mappingValue(templating, dashboardListKey)
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return vars
+	}
+
+	for _, item := range list.Content {
+		name := mappingValue(item, "name")
+		if name == nil || name.Kind != yaml.ScalarNode {
+			continue
+		}
+		value := name.Value
+		if t := mappingValue(item, "type"); t != nil && t.Value == "interval" {
+			value = dashboardGlobalVariables["__interval"]
+		}
+		if current := mappingValue(item, "current"); current != nil {
+			if cv := mappingValue(current, "value"); cv != nil {
+				switch cv.Kind {
+				case yaml.ScalarNode:
+					value = cv.Value
+				case yaml.SequenceNode:
+					values := make([]string, 0, len(cv.Content))
+					for _, v := range cv.Content {
+						values = append(values, v.Value)
+					}
+					value = strings.Join(values, "|")
+				}
+			}
+		}
+		if value == "$__all" || value == "" {
+			value = ".*"
+		}
+		vars[name.Value] = value
+	}
+
+	return vars
+}
+
+// expandDashboardVariables replaces all template variables in a query with
+// their values. Unknown variables are replaced with their name.
+func expandDashboardVariables(expr string, vars map[string]string) string {
+	return dashboardVariableRe.ReplaceAllStringFunc(expr, func(s string) string {
+		m := dashboardVariableRe.FindStringSubmatch(s)
+		name := m[1] + m[2] + m[3]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return name
+	})
+}
+
+// isPrometheusDatasource returns false if given target is using a datasource
+// that's known not to be Prometheus. Targets without a datasource are using
+// the one set on the panel.
+func isPrometheusDatasource(panel, target *yaml.Node) bool {
+	t := datasourceType(target)
+	if t == "" {
+		t = datasourceType(panel)
+	}
+	return t == "" || t == prometheusDatasourceType
+}
+
+func datasourceType(node *yaml.Node) string {
+	ds := mappingValue(node, dashboardDatasourceKey)
+	if ds == nil || ds.Kind != yaml.MappingNode {
+		return ""
+	}
+	if t := mappingValue(ds, "type"); t != nil {
+		return t.Value
+	}
+	return ""
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, val := MappingKeyValue(node, key)
+	return val
+}
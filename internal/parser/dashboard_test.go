@@ -0,0 +1,185 @@
+package parser_test
+
+import (
+	"strconv"
+	"testing"
+
+	promparser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestParseDashboard(t *testing.T) {
+	type queryT struct {
+		path       string
+		lines      []int
+		expr       string
+		err        string
+		positioned bool
+	}
+
+	type testCaseT struct {
+		content string
+		queries []queryT
+		err     string
+	}
+
+	testCases := []testCaseT{
+		{
+			content: "",
+		},
+		{
+			content: "[]",
+			err:     "dashboard must be a JSON object",
+		},
+		{
+			content: "{",
+			err:     "yaml: line 1: did not find expected node content",
+		},
+		{
+			content: `{"title": "empty"}`,
+		},
+		{
+			content: `{
+  "panels": [
+    {
+      "title": "Requests",
+      "targets": [
+        {"expr": "sum(rate(http_requests_total[5m]))", "refId": "A"},
+        {"expr": "", "refId": "B"},
+        {"expr": "sum(rate(http_errors_total{job=\"api\"}[5m]))", "refId": "C"}
+      ]
+    },
+    {
+      "title": "Logs",
+      "datasource": {"type": "loki", "uid": "logs"},
+      "targets": [{"expr": "{job=\"api\"} |= \"error\""}]
+    },
+    {
+      "title": "Mixed",
+      "datasource": {"type": "datasource", "uid": "-- Mixed --"},
+      "targets": [
+        {"expr": "{job=\"api\"}", "datasource": {"type": "loki"}},
+        {"expr": "up{job=\"api\"}", "datasource": {"type": "prometheus"}}
+      ]
+    }
+  ]
+}`,
+			queries: []queryT{
+				{
+					path:       "panels[0].targets[0].expr",
+					lines:      []int{6},
+					expr:       "sum(rate(http_requests_total[5m]))",
+					positioned: true,
+				},
+				{
+					path:       "panels[0].targets[2].expr",
+					lines:      []int{8},
+					expr:       `sum(rate(http_errors_total{job="api"}[5m]))`,
+					positioned: true,
+				},
+				{
+					path:       "panels[2].targets[1].expr",
+					lines:      []int{21},
+					expr:       `up{job="api"}`,
+					positioned: true,
+				},
+			},
+		},
+		{
+			content: `{
+  "dashboard": {
+    "templating": {
+      "list": [
+        {"name": "job", "type": "query", "current": {"value": "api"}},
+        {"name": "instance", "type": "query", "current": {"value": ["a", "b"]}},
+        {"name": "cluster", "type": "query", "current": {"value": "$__all"}},
+        {"name": "step", "type": "interval"}
+      ]
+    },
+    "panels": [
+      {
+        "type": "row",
+        "panels": [
+          {
+            "targets": [
+              {"expr": "rate(foo{job=\"$job\", instance=~\"${instance:regex}\", cluster=~\"[[cluster]]\"}[$__rate_interval])"},
+              {"expr": "avg_over_time(foo[$step]) > $threshold"},
+              {"expr": "sum(foo"}
+            ]
+          }
+        ]
+      }
+    ]
+  }
+}`,
+			queries: []queryT{
+				{
+					path:  "dashboard.panels[0].panels[0].targets[0].expr",
+					lines: []int{17},
+					expr:  `rate(foo{job="api", instance=~"a|b", cluster=~".*"}[5m])`,
+				},
+				{
+					path:  "dashboard.panels[0].panels[0].targets[1].expr",
+					lines: []int{18},
+					expr:  `avg_over_time(foo[1m]) > threshold`,
+				},
+				{
+					path:       "dashboard.panels[0].panels[0].targets[2].expr",
+					lines:      []int{19},
+					expr:       "sum(foo",
+					err:        "no arguments for aggregate expression provided",
+					positioned: true,
+				},
+			},
+		},
+		{
+			content: `{
+  "rows": [
+    {
+      "panels": [
+        {"targets": [{"expr": "up == 0"}]}
+      ]
+    }
+  ]
+}`,
+			queries: []queryT{
+				{
+					path:       "rows[0].panels[0].targets[0].expr",
+					lines:      []int{5},
+					expr:       "up == 0",
+					positioned: true,
+				},
+			},
+		},
+	}
+
+	p := parser.NewParser()
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			rules, err := p.ParseDashboard([]byte(tc.content))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rules, len(tc.queries))
+			for j, q := range tc.queries {
+				dq := rules[j].DashboardQuery
+				require.NotNil(t, dq)
+				require.Nil(t, rules[j].AlertingRule)
+				require.Nil(t, rules[j].RecordingRule)
+				require.Equal(t, q.path, dq.Path)
+				require.Equal(t, q.lines, rules[j].Lines())
+				require.Equal(t, q.expr, rules[j].Expr().Value.Value)
+				if q.err != "" {
+					require.EqualError(t, dq.Expr.SyntaxError, q.err)
+				} else {
+					require.NoError(t, dq.Expr.SyntaxError)
+				}
+				require.Equal(t, q.positioned, dq.Expr.PositionRange(promparser.PositionRange{Start: 0, End: 1}) != nil)
+			}
+		})
+	}
+}
@@ -18,24 +18,29 @@ func DecodeExpr(expr string) (*PromQLNode, error) {
 		if ok := errors.As(err, &perrs); ok {
 			for _, perr := range perrs {
 				pqe.Err = perr.Err
+				pqe.Range = perr.PositionRange
 				pqe.node.Expr = perr.Query
 			}
 		}
 		return nil, pqe
 	}
 
+	pn := decodeNode(node)
+	pn.Expr = expr
+	return pn, nil
+}
+
+// decodeNode keeps all child nodes as returned by the PromQL parser,
+// so positions of every node are relative to the whole query.
+func decodeNode(node promparser.Expr) *PromQLNode {
 	pn := PromQLNode{
-		Expr: expr,
+		Expr: node.String(),
 		Node: node,
 	}
 
 	for _, child := range promparser.Children(node) {
-		c, err := DecodeExpr(child.String())
-		if err != nil {
-			return nil, err
-		}
-		pn.Children = append(pn.Children, c)
+		pn.Children = append(pn.Children, decodeNode(child.(promparser.Expr)))
 	}
 
-	return &pn, nil
+	return &pn
 }
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"errors"
 	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 
@@ -43,6 +45,88 @@ func nodeLines(node *yaml.Node, offset int) (lines []int) {
 	return lines
 }
 
+// Position is a single location in a file, both line and column
+// numbers start from 1.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// PositionRange is a fragment of a file between two positions,
+// both of which are inclusive.
+type PositionRange struct {
+	Start Position
+	End   Position
+}
+
+// mapPositions returns the source position of every byte of the value of
+// given scalar node. YAML allows to write the same string in many different
+// styles, so this walks the source text and the decoded value side by side,
+// skipping indentation, quotes, escape characters and line breaks that don't
+// end up in the value.
+// It returns nil if the value cannot be mapped back to the source.
+func mapPositions(content []byte, node *yaml.Node) (positions []Position) {
+	if node.Kind != yaml.ScalarNode || node.Line == 0 {
+		return nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+	line, col := node.Line, node.Column
+	if node.Style == yaml.LiteralStyle || node.Style == yaml.FoldedStyle {
+		line, col = node.Line+1, 1
+	}
+	if line > len(lines) {
+		return nil
+	}
+	src := []rune(lines[line-1])
+
+	value := node.Value
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		for {
+			if col > len(src) {
+				// end of the source line, a line break in the value or a
+				// space added when folding lines maps to the end of it
+				pos := Position{Line: line, Column: col}
+				line, col = line+1, 1
+				if line > len(lines) && i+size < len(value) {
+					return nil
+				}
+				if line <= len(lines) {
+					src = []rune(lines[line-1])
+				}
+				if r == '\n' || r == ' ' {
+					positions = appendPosition(positions, pos, size)
+					break
+				}
+				continue
+			}
+			s := src[col-1]
+			if s == r {
+				positions = appendPosition(positions, Position{Line: line, Column: col}, size)
+				col++
+				break
+			}
+			switch s {
+			case ' ', '\t', '"', '\'', '\\':
+				col++
+			default:
+				return nil
+			}
+		}
+		i += size
+	}
+
+	return positions
+}
+
+func appendPosition(positions []Position, pos Position, size int) []Position {
+	for i := 0; i < size; i++ {
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
 func NewFilePosition(l []int) FilePosition {
 	return FilePosition{Lines: l}
 }
@@ -166,6 +250,31 @@ func newYamlMap(key, value *yaml.Node, offset int) *YamlMap {
 	return &ym
 }
 
+type YamlList struct {
+	Key   *YamlNode
+	Items []*YamlNode
+}
+
+func (yl YamlList) Lines() (lines []int) {
+	lines = appendLine(lines, yl.Key.Position.Lines...)
+	for _, item := range yl.Items {
+		lines = appendLine(lines, item.Position.Lines...)
+	}
+	return
+}
+
+func newYamlList(key, value *yaml.Node, offset int) *YamlList {
+	yl := YamlList{
+		Key: newYamlNode(key, offset),
+	}
+
+	for _, child := range value.Content {
+		yl.Items = append(yl.Items, newYamlNode(child, offset))
+	}
+
+	return &yl
+}
+
 type PromQLNode struct {
 	Expr     string
 	Node     promparser.Expr
@@ -173,8 +282,9 @@ type PromQLNode struct {
 }
 
 type PromQLError struct {
-	node *PromQLNode
-	Err  error
+	node  *PromQLNode
+	Err   error
+	Range promparser.PositionRange
 }
 
 func (pqle PromQLError) Error() string {
@@ -194,6 +304,9 @@ type PromQLExpr struct {
 	Value       *YamlNode
 	SyntaxError error
 	Query       *PromQLNode
+
+	// position of every byte of Value.Value in the source file
+	positions []Position
 }
 
 func (pqle PromQLExpr) Lines() (lines []int) {
@@ -202,12 +315,53 @@ func (pqle PromQLExpr) Lines() (lines []int) {
 	return
 }
 
-func newPromQLExpr(key, val *yaml.Node, offset int) *PromQLExpr {
+// PositionRange returns the location in the source file of a part of the
+// query described by a PromQL AST position range, which is a byte offset
+// into the query string.
+// It returns nil if the location is unknown, for example because the query
+// was embedded inside another YAML string.
+func (pqle PromQLExpr) PositionRange(pr promparser.PositionRange) *PositionRange {
+	start, end := int(pr.Start), int(pr.End)
+	if start >= len(pqle.positions) {
+		// errors like unexpected end of input point past the last character
+		start = len(pqle.positions) - 1
+	}
+	if end <= start {
+		end = start + 1
+	}
+	if end > len(pqle.positions) {
+		end = len(pqle.positions)
+	}
+	if start < 0 || start >= end {
+		return nil
+	}
+	return &PositionRange{
+		Start: pqle.positions[start],
+		End:   pqle.positions[end-1],
+	}
+}
+
+// SyntaxErrorRange returns the location of the syntax error in the source file.
+func (pqle PromQLExpr) SyntaxErrorRange() *PositionRange {
+	var perr PromQLError
+	if !errors.As(pqle.SyntaxError, &perr) {
+		return nil
+	}
+	return pqle.PositionRange(perr.Range)
+}
+
+func newPromQLExpr(content []byte, key, val *yaml.Node, offset int) *PromQLExpr {
 	expr := PromQLExpr{
 		Key:   newYamlNode(key, offset),
 		Value: newYamlNodeWithParent(key, val, offset),
 	}
 
+	// positions of nodes from strings embedded in another YAML document
+	// are relative to that string and not to the file
+	if offset == 0 {
+		expr.positions = mapPositions(content, val)
+	}
+
 	qlNode, err := DecodeExpr(val.Value)
 	if err != nil {
 		expr.SyntaxError = err
@@ -219,11 +373,12 @@ func newPromQLExpr(key, val *yaml.Node, offset int) *PromQLExpr {
 }
 
 type AlertingRule struct {
-	Alert       YamlKeyValue
-	Expr        PromQLExpr
-	For         *YamlKeyValue
-	Labels      *YamlMap
-	Annotations *YamlMap
+	Alert         YamlKeyValue
+	Expr          PromQLExpr
+	For           *YamlKeyValue
+	KeepFiringFor *YamlKeyValue
+	Labels        *YamlMap
+	Annotations   *YamlMap
 }
 
 func (ar AlertingRule) Lines() (lines []int) {
@@ -232,6 +387,9 @@ func (ar AlertingRule) Lines() (lines []int) {
 	if ar.For != nil {
 		lines = appendLine(lines, ar.For.Lines()...)
 	}
+	if ar.KeepFiringFor != nil {
+		lines = appendLine(lines, ar.KeepFiringFor.Lines()...)
+	}
 	if ar.Labels != nil {
 		lines = appendLine(lines, ar.Labels.Lines()...)
 	}
@@ -250,6 +408,10 @@ func (ar AlertingRule) Comments() (comments []string) {
 		comments = append(comments, ar.For.Key.Comments...)
 		comments = append(comments, ar.For.Value.Comments...)
 	}
+	if ar.KeepFiringFor != nil {
+		comments = append(comments, ar.KeepFiringFor.Key.Comments...)
+		comments = append(comments, ar.KeepFiringFor.Value.Comments...)
+	}
 	if ar.Labels != nil {
 		comments = append(comments, ar.Labels.Key.Comments...)
 		for _, label := range ar.Labels.Items {
@@ -297,6 +459,54 @@ func (rr RecordingRule) Comments() (comments []string) {
 	return
 }
 
+type RuleGroup struct {
+	Name                    YamlKeyValue
+	Interval                *YamlKeyValue
+	Limit                   *YamlKeyValue
+	PartialResponseStrategy *YamlKeyValue
+	// Cortex, Mimir and Thanos ruler extensions.
+	Namespace       *YamlKeyValue
+	SourceTenants   *YamlList
+	EvaluationDelay *YamlKeyValue
+	QueryOffset     *YamlKeyValue
+}
+
+func (rg RuleGroup) Lines() (lines []int) {
+	lines = appendLine(lines, rg.Name.Lines()...)
+	if rg.Interval != nil {
+		lines = appendLine(lines, rg.Interval.Lines()...)
+	}
+	if rg.Limit != nil {
+		lines = appendLine(lines, rg.Limit.Lines()...)
+	}
+	if rg.PartialResponseStrategy != nil {
+		lines = appendLine(lines, rg.PartialResponseStrategy.Lines()...)
+	}
+	if rg.SourceTenants != nil {
+		lines = appendLine(lines, rg.SourceTenants.Lines()...)
+	}
+	if rg.EvaluationDelay != nil {
+		lines = appendLine(lines, rg.EvaluationDelay.Lines()...)
+	}
+	if rg.QueryOffset != nil {
+		lines = appendLine(lines, rg.QueryOffset.Lines()...)
+	}
+	return
+}
+
+// Tenants returns the list of source_tenants set on this group.
+// The tenant a group is loaded into isn't part of rule files, so groups
+// without source_tenants have no tenants.
+func (rg RuleGroup) Tenants() (tenants []string) {
+	if rg.SourceTenants == nil {
+		return nil
+	}
+	for _, item := range rg.SourceTenants.Items {
+		tenants = append(tenants, item.Value)
+	}
+	return tenants
+}
+
 type ParseError struct {
 	Fragment string
 	Err      error
@@ -304,15 +514,21 @@ type ParseError struct {
 }
 
 type Rule struct {
-	AlertingRule  *AlertingRule
-	RecordingRule *RecordingRule
-	Error         ParseError
+	AlertingRule   *AlertingRule
+	RecordingRule  *RecordingRule
+	DashboardQuery *DashboardQuery
+	Group          *RuleGroup
+	Document       int
+	Error          ParseError
 }
 
 func (r Rule) Expr() PromQLExpr {
 	if r.RecordingRule != nil {
 		return r.RecordingRule.Expr
 	}
+	if r.DashboardQuery != nil {
+		return r.DashboardQuery.Expr
+	}
 	return r.AlertingRule.Expr
 }
 
@@ -323,6 +539,9 @@ func (r Rule) Lines() []int {
 	if r.AlertingRule != nil {
 		return r.AlertingRule.Lines()
 	}
+	if r.DashboardQuery != nil {
+		return r.DashboardQuery.Lines()
+	}
 	if r.Error.Err != nil {
 		return []int{r.Error.Line}
 	}
@@ -1,19 +1,35 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	recordKey      = "record"
-	exprKey        = "expr"
-	labelsKey      = "labels"
-	alertKey       = "alert"
-	forKey         = "for"
-	annotationsKey = "annotations"
+	recordKey        = "record"
+	exprKey          = "expr"
+	labelsKey        = "labels"
+	alertKey         = "alert"
+	forKey           = "for"
+	keepFiringForKey = "keep_firing_for"
+	annotationsKey   = "annotations"
+
+	groupNameKey            = "name"
+	groupIntervalKey        = "interval"
+	groupLimitKey           = "limit"
+	groupPartialResponseKey = "partial_response_strategy"
+	groupRulesKey           = "rules"
+
+	namespaceKey            = "namespace"
+	groupsKey               = "groups"
+	groupSourceTenantsKey   = "source_tenants"
+	groupEvaluationDelayKey = "evaluation_delay"
+	groupQueryOffsetKey     = "query_offset"
 )
 
 func NewParser() Parser {
@@ -33,30 +49,52 @@ func (p Parser) Parse(content []byte) (rules []Rule, err error) {
 		}
 	}()
 
-	var node yaml.Node
-	err = yaml.Unmarshal(content, &node)
-	if err != nil {
-		return nil, err
-	}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for doc := 0; ; doc++ {
+		var node yaml.Node
+		err = dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return rules, nil
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	return parseNode(content, &node, 0)
+		var ret []Rule
+		ret, err = parseNode(content, &node, 0, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ret {
+			ret[i].Document = doc
+		}
+		rules = append(rules, ret...)
+	}
 }
 
-func parseNode(content []byte, node *yaml.Node, offset int) (rules []Rule, err error) {
+func parseNode(content []byte, node *yaml.Node, offset int, namespace *YamlKeyValue, group *RuleGroup) (rules []Rule, err error) {
 	ret, isEmpty, err := parseRule(content, node, offset)
 	if err != nil {
 		return nil, err
 	}
 	if !isEmpty {
+		ret.Group = group
 		rules = append(rules, ret)
 		return
 	}
 
+	if ns := parseNamespace(node, offset); ns != nil {
+		namespace = ns
+	}
+	if g := parseGroup(node, offset, namespace); g != nil {
+		group = g
+	}
+
 	for _, root := range node.Content {
 		switch root.Kind {
 		case yaml.SequenceNode:
 			for _, n := range root.Content {
-				ret, err := parseNode(content, n, offset)
+				ret, err := parseNode(content, n, offset, namespace, group)
 				if err != nil {
 					return nil, err
 				}
@@ -68,10 +106,19 @@ func parseNode(content []byte, node *yaml.Node, offset int) (rules []Rule, err e
 				return nil, err
 			}
 			if !isEmpty {
+				rule.Group = group
 				rules = append(rules, rule)
 			} else {
+				rootNamespace := namespace
+				if ns := parseNamespace(root, offset); ns != nil {
+					rootNamespace = ns
+				}
+				rootGroup := group
+				if g := parseGroup(root, offset, rootNamespace); g != nil {
+					rootGroup = g
+				}
 				for _, n := range root.Content {
-					ret, err := parseNode(content, n, offset)
+					ret, err := parseNode(content, n, offset, rootNamespace, rootGroup)
 					if err != nil {
 						return nil, err
 					}
@@ -84,7 +131,7 @@ func parseNode(content []byte, node *yaml.Node, offset int) (rules []Rule, err e
 				var n yaml.Node
 				err = yaml.Unmarshal(c, &n)
 				if err == nil {
-					ret, err := parseNode(c, &n, offset+root.Line)
+					ret, err := parseNode(c, &n, offset+root.Line, namespace, group)
 					if err != nil {
 						return nil, err
 					}
@@ -109,6 +156,7 @@ func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty
 
 	var alertPart *YamlKeyValue
 	var forPart *YamlKeyValue
+	var keepFiringForPart *YamlKeyValue
 	var annotationsPart *YamlMap
 
 	var key *yaml.Node
@@ -139,12 +187,17 @@ func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty
 				if exprPart != nil {
 					return duplicatedKeyError(part.Line+offset, exprKey, nil)
 				}
-				exprPart = newPromQLExpr(key, part, offset)
+				exprPart = newPromQLExpr(content, key, part, offset)
 			case forKey:
 				if forPart != nil {
 					return duplicatedKeyError(part.Line+offset, forKey, nil)
 				}
 				forPart = newYamlKeyValue(key, part, offset)
+			case keepFiringForKey:
+				if keepFiringForPart != nil {
+					return duplicatedKeyError(part.Line+offset, keepFiringForKey, nil)
+				}
+				keepFiringForPart = newYamlKeyValue(key, part, offset)
 			case labelsKey:
 				if labelsPart != nil {
 					return duplicatedKeyError(part.Line+offset, labelsKey, nil)
@@ -250,11 +303,12 @@ func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty
 	if alertPart != nil && exprPart != nil {
 		isEmpty = false
 		rule = Rule{AlertingRule: &AlertingRule{
-			Alert:       *alertPart,
-			Expr:        *exprPart,
-			For:         forPart,
-			Labels:      labelsPart,
-			Annotations: annotationsPart,
+			Alert:         *alertPart,
+			Expr:          *exprPart,
+			For:           forPart,
+			KeepFiringFor: keepFiringForPart,
+			Labels:        labelsPart,
+			Annotations:   annotationsPart,
 		}}
 		return
 	}
@@ -262,6 +316,86 @@ func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty
 	return
 }
 
+// parseNamespace returns the namespace key from a Cortex, Mimir or Thanos
+// ruler file, which wraps a list of groups into a namespace.
+func parseNamespace(node *yaml.Node, offset int) *YamlKeyValue {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var namespace *YamlKeyValue
+	var hasGroups bool
+	var key *yaml.Node
+	for i, part := range unpackNodes(node) {
+		if i%2 == 0 {
+			key = part
+			continue
+		}
+		switch key.Value {
+		case namespaceKey:
+			if part.Kind != yaml.ScalarNode {
+				return nil
+			}
+			namespace = newYamlKeyValue(key, part, offset)
+		case groupsKey:
+			hasGroups = part.Kind == yaml.SequenceNode
+		}
+	}
+
+	if !hasGroups {
+		return nil
+	}
+	return namespace
+}
+
+func parseGroup(node *yaml.Node, offset int, namespace *YamlKeyValue) *RuleGroup {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	group := RuleGroup{Namespace: namespace}
+	var hasName, hasRules bool
+	var key *yaml.Node
+	for i, part := range unpackNodes(node) {
+		if i%2 == 0 {
+			key = part
+			continue
+		}
+		switch key.Value {
+		case groupNameKey:
+			if part.Kind != yaml.ScalarNode {
+				return nil
+			}
+			hasName = true
+			group.Name = *newYamlKeyValue(key, part, offset)
+		case groupIntervalKey:
+			group.Interval = newYamlKeyValue(key, part, offset)
+		case groupLimitKey:
+			group.Limit = newYamlKeyValue(key, part, offset)
+		case groupPartialResponseKey:
+			group.PartialResponseStrategy = newYamlKeyValue(key, part, offset)
+		case groupSourceTenantsKey:
+			if part.Kind == yaml.SequenceNode {
+				group.SourceTenants = newYamlList(key, part, offset)
+			}
+		case groupEvaluationDelayKey:
+			group.EvaluationDelay = newYamlKeyValue(key, part, offset)
+		case groupQueryOffsetKey:
+			group.QueryOffset = newYamlKeyValue(key, part, offset)
+		case groupRulesKey:
+			if part.Kind != yaml.SequenceNode {
+				return nil
+			}
+			hasRules = true
+		}
+	}
+
+	if !hasName || !hasRules {
+		return nil
+	}
+	return &group
+}
+
 func unpackNodes(node *yaml.Node) []*yaml.Node {
 	nodes := make([]*yaml.Node, 0, len(node.Content))
 	var isMerge bool
@@ -308,6 +442,20 @@ func hasKey(node *yaml.Node, key string) bool {
 	return false
 }
 
+// MappingKeyValue returns the key and value nodes for given key
+// if node is a mapping, or nils otherwise.
+func MappingKeyValue(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
 func resolveMapAlias(part, parent *yaml.Node) *yaml.Node {
 	node := yaml.Node(*part)
 	node.Content = nil
@@ -3,11 +3,13 @@ package parser_test
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/cloudflare/pint/internal/parser"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	promparser "github.com/prometheus/prometheus/promql/parser"
 )
 
@@ -125,6 +127,74 @@ func TestParse(t *testing.T) {
 		},
 		{
 			content: []byte(`
+- alert: foo
+  keep_firing_for: 5m
+  expr: bar
+  keep_firing_for: 1m
+`),
+			output: []parser.Rule{
+				{Error: parser.ParseError{Err: fmt.Errorf("duplicated keep_firing_for key"), Line: 5}},
+			},
+		},
+		{
+			content: []byte(`
+- alert: foo
+  expr: bar
+  for: 5m
+  keep_firing_for: 10m
+`),
+			output: []parser.Rule{
+				{
+					AlertingRule: &parser.AlertingRule{
+						Alert: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "alert",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "foo",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "bar",
+							},
+							Query: &parser.PromQLNode{
+								Expr: "bar",
+							},
+						},
+						For: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "for",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "5m",
+							},
+						},
+						KeepFiringFor: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "keep_firing_for",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "10m",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			content: []byte(`
 - alert: foo
   labels: {}
   expr: bar
@@ -477,6 +547,18 @@ groups:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "custom_rules",
+							},
+						},
+					},
 				},
 			},
 			shouldError: false,
@@ -817,6 +899,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "example-app-alerts",
+							},
+						},
+					},
 				},
 				{
 					AlertingRule: &parser.AlertingRule{
@@ -874,6 +968,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "example-app-alerts",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -992,6 +1098,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "haproxy.api_server.rules",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -1031,6 +1149,18 @@ data:
 							Query: &parser.PromQLNode{Expr: "expr1"},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
 				},
 				{
 					RecordingRule: &parser.RecordingRule{
@@ -1056,6 +1186,18 @@ data:
 							Query: &parser.PromQLNode{Expr: "expr2"},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
 				},
 				{
 					RecordingRule: &parser.RecordingRule{
@@ -1081,6 +1223,18 @@ data:
 							Query: &parser.PromQLNode{Expr: "expr1"},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -1151,6 +1305,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
 				},
 				{
 					RecordingRule: &parser.RecordingRule{
@@ -1205,6 +1371,434 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			content: []byte(`groups:
+- name: foo
+  interval: 1m
+  limit: 5
+  partial_response_strategy: warn
+  rules:
+  - record: name1
+    expr: expr1
+- name: bar
+  rules:
+  - alert: name2
+    expr: expr2
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "name1",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{8}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{8}},
+								Value:    "expr1",
+							},
+							Query: &parser.PromQLNode{Expr: "expr1"},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "foo",
+							},
+						},
+						Interval: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "interval",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "1m",
+							},
+						},
+						Limit: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "limit",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "5",
+							},
+						},
+						PartialResponseStrategy: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "partial_response_strategy",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "warn",
+							},
+						},
+					},
+				},
+				{
+					AlertingRule: &parser.AlertingRule{
+						Alert: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "alert",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "name2",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{12}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{12}},
+								Value:    "expr2",
+							},
+							Query: &parser.PromQLNode{Expr: "expr2"},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{9}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{9}},
+								Value:    "bar",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			content: []byte(`groups:
+- rules:
+  - record: name1
+    expr: expr1
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "name1",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "expr1",
+							},
+							Query: &parser.PromQLNode{Expr: "expr1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			content: []byte(`apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example
+spec:
+  groups:
+  - name: foo
+    rules:
+    - record: name1
+      expr: expr1
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example2
+spec:
+  groups:
+  - name: bar
+    rules:
+    - alert: name2
+      expr: expr2
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{9}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{9}},
+								Value:    "name1",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{10}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{10}},
+								Value:    "expr1",
+							},
+							Query: &parser.PromQLNode{Expr: "expr1"},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "foo",
+							},
+						},
+					},
+				},
+				{
+					AlertingRule: &parser.AlertingRule{
+						Alert: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{20}},
+								Value:    "alert",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{20}},
+								Value:    "name2",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{21}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{21}},
+								Value:    "expr2",
+							},
+							Query: &parser.PromQLNode{Expr: "expr2"},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{18}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{18}},
+								Value:    "bar",
+							},
+						},
+					},
+					Document: 1,
+				},
+			},
+		},
+		{
+			content: []byte("- record: name1\n  expr: expr1\n---\n# comment\n---\n- record: name2\n  expr: expr2\n"),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{1}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{1}},
+								Value:    "name1",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "expr1",
+							},
+							Query: &parser.PromQLNode{Expr: "expr1"},
+						},
+					},
+				},
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{6}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{6}},
+								Value:    "name2",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "expr2",
+							},
+							Query: &parser.PromQLNode{Expr: "expr2"},
+						},
+					},
+					Document: 2,
+				},
+			},
+		},
+		{
+			content:     []byte("- record: name1\n  expr: expr1\n---\n- record: name2\n  expr: expr2\n  expr: expr3\n---\n- record: {}\n  expr: [\n"),
+			output:      nil,
+			shouldError: true,
+		},
+		{
+			content: []byte(`namespace: ns1
+groups:
+- name: foo
+  source_tenants:
+  - tenant-a
+  - tenant-b
+  evaluation_delay: 2m
+  query_offset: 1m
+  rules:
+  - record: name1
+    expr: expr1
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{10}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{10}},
+								Value:    "name1",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "expr1",
+							},
+							Query: &parser.PromQLNode{Expr: "expr1"},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "foo",
+							},
+						},
+						Namespace: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{1}},
+								Value:    "namespace",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{1}},
+								Value:    "ns1",
+							},
+						},
+						SourceTenants: &parser.YamlList{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "source_tenants",
+							},
+							Items: []*parser.YamlNode{
+								{
+									Position: parser.FilePosition{Lines: []int{5}},
+									Value:    "tenant-a",
+								},
+								{
+									Position: parser.FilePosition{Lines: []int{6}},
+									Value:    "tenant-b",
+								},
+							},
+						},
+						EvaluationDelay: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "evaluation_delay",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "2m",
+							},
+						},
+						QueryOffset: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{8}},
+								Value:    "query_offset",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{8}},
+								Value:    "1m",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -1239,10 +1833,95 @@ data:
 				return
 			}
 
-			if diff := cmp.Diff(tc.output, output, ignorePrometheusExpr, sameErrorText); diff != "" {
+			if diff := cmp.Diff(tc.output, output, ignorePrometheusExpr, sameErrorText, cmpopts.IgnoreUnexported(parser.PromQLExpr{})); diff != "" {
 				t.Errorf("Parse() returned wrong output (-want +got):\n%s", diff)
 				return
 			}
 		})
 	}
 }
+
+func TestPositionRange(t *testing.T) {
+	type testCaseT struct {
+		content  string
+		fragment string
+		output   *parser.PositionRange
+	}
+
+	testCases := []testCaseT{
+		{
+			content:  "- record: foo\n  expr: sum(foo{job=\"bar\"})\n",
+			fragment: `foo{job="bar"}`,
+			output: &parser.PositionRange{
+				Start: parser.Position{Line: 2, Column: 13},
+				End:   parser.Position{Line: 2, Column: 26},
+			},
+		},
+		{
+			content:  "- record: foo\n  expr: 'sum(foo{job=\"bar\"})'\n",
+			fragment: `foo{job="bar"}`,
+			output: &parser.PositionRange{
+				Start: parser.Position{Line: 2, Column: 14},
+				End:   parser.Position{Line: 2, Column: 27},
+			},
+		},
+		{
+			content:  "- record: foo\n  expr: \"sum(foo{job=\\\"bar\\\"})\"\n",
+			fragment: `foo{job="bar"}`,
+			output: &parser.PositionRange{
+				Start: parser.Position{Line: 2, Column: 14},
+				End:   parser.Position{Line: 2, Column: 29},
+			},
+		},
+		{
+			content:  "- record: foo\n  expr: |\n    sum(\n      foo{job=\"bar\"}\n    )\n",
+			fragment: `foo{job="bar"}`,
+			output: &parser.PositionRange{
+				Start: parser.Position{Line: 4, Column: 7},
+				End:   parser.Position{Line: 4, Column: 20},
+			},
+		},
+		{
+			content:  "- record: foo\n  expr: >\n    sum(foo)\n    / sum(bar)\n",
+			fragment: "sum(bar)",
+			output: &parser.PositionRange{
+				Start: parser.Position{Line: 4, Column: 7},
+				End:   parser.Position{Line: 4, Column: 14},
+			},
+		},
+		{
+			content:  "- record: foo\n  expr: sum(foo)\n    / sum(bar)\n",
+			fragment: "sum(foo) / sum(bar)",
+			output: &parser.PositionRange{
+				Start: parser.Position{Line: 2, Column: 9},
+				End:   parser.Position{Line: 3, Column: 14},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			p := parser.NewParser()
+			rules, err := p.Parse([]byte(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rules) != 1 || rules[0].RecordingRule == nil {
+				t.Fatalf("expected a single recording rule, got %v", rules)
+			}
+
+			expr := rules[0].RecordingRule.Expr
+			start := strings.Index(expr.Value.Value, tc.fragment)
+			if start < 0 {
+				t.Fatalf("fragment %q not found in %q", tc.fragment, expr.Value.Value)
+			}
+			output := expr.PositionRange(promparser.PositionRange{
+				Start: promparser.Pos(start),
+				End:   promparser.Pos(start + len(tc.fragment)),
+			})
+			if diff := cmp.Diff(tc.output, output); diff != "" {
+				t.Errorf("PositionRange() returned wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
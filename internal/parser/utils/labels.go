@@ -0,0 +1,480 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// RecordingRules maps recording rule names to all of their definitions.
+type RecordingRules map[string][]parser.RecordingRule
+
+// NewRecordingRules returns all valid recording rules from given list.
+func NewRecordingRules(rules []parser.Rule) RecordingRules {
+	rr := RecordingRules{}
+	for _, rule := range rules {
+		if rule.RecordingRule == nil || rule.Error.Err != nil {
+			continue
+		}
+		name := rule.RecordingRule.Record.Value.Value
+		rr[name] = append(rr[name], *rule.RecordingRule)
+	}
+	return rr
+}
+
+// LabelSet describes labels present on all results of a PromQL expression.
+// The __name__ label is never included.
+type LabelSet struct {
+	// Fixed is true when all labels that results can have are known
+	// and listed in Possible.
+	Fixed bool
+	// Possible is the list of all labels results can have.
+	// It's only set when Fixed is true.
+	Possible []string
+	// Guaranteed is the list of labels that will be present on every result.
+	Guaranteed []string
+	// Excluded is the list of labels that will never be present on results.
+	// It's only set when Fixed is false.
+	Excluded []string
+	// Values maps guaranteed labels to their value, if it's known to be
+	// the same on every result, for example because of an equality matcher.
+	Values map[string]string
+}
+
+// CanHave returns true if results might have given label.
+func (ls LabelSet) CanHave(name string) bool {
+	if ls.Fixed {
+		return containsLabel(ls.Possible, name)
+	}
+	return !containsLabel(ls.Excluded, name)
+}
+
+// MustHave returns true if every result will have given label.
+func (ls LabelSet) MustHave(name string) bool {
+	return containsLabel(ls.Guaranteed, name)
+}
+
+// Value returns the value of given label if it's the same on every result.
+func (ls LabelSet) Value(name string) (string, bool) {
+	v, ok := ls.Values[name]
+	return v, ok
+}
+
+// add marks given label as present, any known value of it is dropped.
+func (ls LabelSet) add(name string, guaranteed bool) LabelSet {
+	if ls.Fixed {
+		ls.Possible = appendLabel(ls.Possible, name)
+	}
+	ls.Excluded = removeLabels(ls.Excluded, name)
+	if guaranteed {
+		ls.Guaranteed = appendLabel(ls.Guaranteed, name)
+	}
+	ls.Values = removeValues(ls.Values, name)
+	return ls
+}
+
+// addValue marks given label as present on every result with given value.
+func (ls LabelSet) addValue(name, value string) LabelSet {
+	ls = ls.add(name, true)
+	if !ls.MustHave(name) {
+		return ls
+	}
+	// copy values first, it might be shared with another label set
+	values := make(map[string]string, len(ls.Values)+1)
+	for k, v := range ls.Values {
+		values[k] = v
+	}
+	values[name] = value
+	ls.Values = values
+	return ls
+}
+
+func (ls LabelSet) remove(names ...string) LabelSet {
+	if ls.Fixed {
+		ls.Possible = removeLabels(ls.Possible, names...)
+	} else {
+		for _, name := range names {
+			ls.Excluded = appendLabel(ls.Excluded, name)
+		}
+	}
+	ls.Guaranteed = removeLabels(ls.Guaranteed, names...)
+	ls.Values = removeValues(ls.Values, names...)
+	return ls
+}
+
+func (ls LabelSet) keep(names ...string) LabelSet {
+	keep := LabelSet{Fixed: true}
+	for _, name := range names {
+		if ls.CanHave(name) {
+			keep.Possible = appendLabel(keep.Possible, name)
+		}
+		if v, ok := ls.Value(name); ok {
+			keep = keep.addValue(name, v)
+		} else if ls.MustHave(name) {
+			keep.Guaranteed = appendLabel(keep.Guaranteed, name)
+		}
+	}
+	return keep
+}
+
+// merge returns a label set describing results that can come from either set.
+func (ls LabelSet) merge(o LabelSet) LabelSet {
+	m := LabelSet{Fixed: ls.Fixed && o.Fixed}
+	if m.Fixed {
+		for _, name := range ls.Possible {
+			m.Possible = appendLabel(m.Possible, name)
+		}
+		for _, name := range o.Possible {
+			m.Possible = appendLabel(m.Possible, name)
+		}
+	} else {
+		for _, name := range append(append([]string{}, ls.Excluded...), o.Excluded...) {
+			if !ls.CanHave(name) && !o.CanHave(name) {
+				m.Excluded = appendLabel(m.Excluded, name)
+			}
+		}
+	}
+	for _, name := range ls.Guaranteed {
+		if !o.MustHave(name) {
+			continue
+		}
+		v, ok := ls.Value(name)
+		if ov, ook := o.Value(name); ok && ook && v == ov {
+			m = m.addValue(name, v)
+		} else {
+			m.Guaranteed = appendLabel(m.Guaranteed, name)
+		}
+	}
+	return m
+}
+
+// OutputLabels returns the set of labels that will be present on results
+// of given PromQL expression. Recording rules used by the query are resolved
+// using passed rules, other selectors are assumed to return any label.
+func OutputLabels(node *parser.PromQLNode, rules RecordingRules) LabelSet {
+	return outputLabels(node.Node, rules, map[string]bool{})
+}
+
+func outputLabels(node promParser.Node, rules RecordingRules, visited map[string]bool) LabelSet {
+	switch n := node.(type) {
+	case *promParser.NumberLiteral, *promParser.StringLiteral:
+		return LabelSet{Fixed: true}
+	case *promParser.ParenExpr:
+		return outputLabels(n.Expr, rules, visited)
+	case *promParser.UnaryExpr:
+		return outputLabels(n.Expr, rules, visited)
+	case *promParser.StepInvariantExpr:
+		return outputLabels(n.Expr, rules, visited)
+	case *promParser.SubqueryExpr:
+		return outputLabels(n.Expr, rules, visited)
+	case *promParser.MatrixSelector:
+		return outputLabels(n.VectorSelector, rules, visited)
+	case *promParser.VectorSelector:
+		return selectorLabels(n, rules, visited)
+	case *promParser.AggregateExpr:
+		return aggregationLabels(n, rules, visited)
+	case *promParser.Call:
+		return callLabels(n, rules, visited)
+	case *promParser.BinaryExpr:
+		return binaryExprLabels(n, rules, visited)
+	}
+	return LabelSet{}
+}
+
+func selectorLabels(n *promParser.VectorSelector, rules RecordingRules, visited map[string]bool) LabelSet {
+	var ls LabelSet
+	if defs, ok := rules[n.Name]; ok && !visited[n.Name] {
+		visited[n.Name] = true
+		for i, rr := range defs {
+			rls := LabelSet{}
+			if rr.Expr.Query != nil {
+				rls = outputLabels(rr.Expr.Query.Node, rules, visited)
+			}
+			if rr.Labels != nil {
+				for _, label := range rr.Labels.Items {
+					rls = rls.addValue(label.Key.Value, label.Value.Value)
+				}
+			}
+			if i == 0 {
+				ls = rls
+			} else {
+				ls = ls.merge(rls)
+			}
+		}
+		delete(visited, n.Name)
+	}
+
+	for _, lm := range n.LabelMatchers {
+		if lm.Name == labels.MetricName {
+			continue
+		}
+		switch {
+		case lm.Type == labels.MatchEqual && lm.Value == "":
+			ls = ls.remove(lm.Name)
+		case lm.Type == labels.MatchEqual:
+			ls = ls.addValue(lm.Name, lm.Value)
+		}
+	}
+
+	return ls
+}
+
+func aggregationLabels(n *promParser.AggregateExpr, rules RecordingRules, visited map[string]bool) LabelSet {
+	ls := outputLabels(n.Expr, rules, visited)
+
+	switch n.Op {
+	case promParser.TOPK, promParser.BOTTOMK:
+		return ls
+	}
+
+	if n.Without {
+		ls = ls.remove(n.Grouping...)
+	} else {
+		ls = ls.keep(n.Grouping...)
+	}
+
+	if n.Op == promParser.COUNT_VALUES {
+		if s, ok := n.Param.(*promParser.StringLiteral); ok {
+			ls = ls.add(s.Val, true)
+		}
+	}
+
+	return ls
+}
+
+func callLabels(n *promParser.Call, rules RecordingRules, visited map[string]bool) LabelSet {
+	if n.Func.ReturnType != promParser.ValueTypeVector {
+		return LabelSet{Fixed: true}
+	}
+
+	switch n.Func.Name {
+	case "absent", "absent_over_time":
+		return absentLabels(n)
+	case "label_replace":
+		ls := outputLabels(n.Args[0], rules, visited)
+		dst := stringArg(n.Args, 1)
+		repl := stringArg(n.Args, 2)
+		src := stringArg(n.Args, 3)
+		regex := stringArg(n.Args, 4)
+		if repl == "" && alwaysMatches(regex, src) {
+			// setting a label to an empty value removes it
+			return ls.remove(dst)
+		}
+		if repl != "" && !strings.Contains(repl, "$") && alwaysMatches(regex, src) {
+			return ls.addValue(dst, repl)
+		}
+		if repl == "" {
+			// an empty replacement will remove dst from any result it matches
+			return ls.remove(dst).add(dst, false)
+		}
+		return ls.add(dst, ls.MustHave(dst))
+	case "label_join":
+		ls := outputLabels(n.Args[0], rules, visited)
+		// dst label is only set if at least one source label is not empty
+		var guaranteed bool
+		for i := 3; i < len(n.Args); i++ {
+			if ls.MustHave(stringArg(n.Args, i)) {
+				guaranteed = true
+			}
+		}
+		return ls.add(stringArg(n.Args, 1), guaranteed)
+	case "histogram_quantile":
+		return outputLabels(n.Args[1], rules, visited).remove("le")
+	}
+
+	for _, arg := range n.Args {
+		if arg.Type() == promParser.ValueTypeVector || arg.Type() == promParser.ValueTypeMatrix {
+			return outputLabels(arg, rules, visited)
+		}
+	}
+
+	return LabelSet{Fixed: true}
+}
+
+// absentLabels returns labels that absent() will copy from equality matchers.
+func absentLabels(n *promParser.Call) LabelSet {
+	ls := LabelSet{Fixed: true}
+
+	var vs *promParser.VectorSelector
+	switch arg := n.Args[0].(type) {
+	case *promParser.VectorSelector:
+		vs = arg
+	case *promParser.MatrixSelector:
+		vs, _ = arg.VectorSelector.(*promParser.VectorSelector)
+	}
+	if vs == nil {
+		return ls
+	}
+
+	counts := map[string]int{}
+	values := map[string]string{}
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name != labels.MetricName && lm.Type == labels.MatchEqual {
+			counts[lm.Name]++
+			values[lm.Name] = lm.Value
+		}
+	}
+	for name, count := range counts {
+		// labels with multiple equality matchers are not copied
+		if count == 1 {
+			ls = ls.addValue(name, values[name])
+		}
+	}
+
+	return ls
+}
+
+func binaryExprLabels(n *promParser.BinaryExpr, rules RecordingRules, visited map[string]bool) LabelSet {
+	lhsVec := n.LHS.Type() == promParser.ValueTypeVector
+	rhsVec := n.RHS.Type() == promParser.ValueTypeVector
+	switch {
+	case !lhsVec && !rhsVec:
+		return LabelSet{Fixed: true}
+	case !rhsVec:
+		return outputLabels(n.LHS, rules, visited)
+	case !lhsVec:
+		return outputLabels(n.RHS, rules, visited)
+	}
+
+	lhs := outputLabels(n.LHS, rules, visited)
+	rhs := outputLabels(n.RHS, rules, visited)
+
+	switch n.Op {
+	case promParser.LAND, promParser.LUNLESS:
+		return lhs
+	case promParser.LOR:
+		return lhs.merge(rhs)
+	}
+
+	if n.VectorMatching == nil {
+		return lhs
+	}
+
+	switch n.VectorMatching.Card {
+	case promParser.CardOneToOne:
+		if n.VectorMatching.On {
+			ls := lhs.keep(n.VectorMatching.MatchingLabels...)
+			for _, name := range n.VectorMatching.MatchingLabels {
+				// both sides must have the same value of matching labels
+				if v, ok := rhs.Value(name); ok && !ls.MustHave(name) {
+					ls = ls.addValue(name, v)
+				} else if rhs.MustHave(name) && !ls.MustHave(name) {
+					ls = ls.add(name, true)
+				}
+			}
+			return ls
+		}
+		return lhs.remove(n.VectorMatching.MatchingLabels...)
+	case promParser.CardManyToOne:
+		return includeLabels(lhs, rhs, n.VectorMatching.Include)
+	case promParser.CardOneToMany:
+		return includeLabels(rhs, lhs, n.VectorMatching.Include)
+	}
+
+	return lhs
+}
+
+// includeLabels returns labels of the "many" side of a group_left or
+// group_right join, with included labels copied from the "one" side.
+func includeLabels(many, one LabelSet, include []string) LabelSet {
+	ls := many
+	for _, name := range include {
+		switch {
+		case !one.CanHave(name):
+			ls = ls.remove(name)
+		case one.MustHave(name):
+			if v, ok := one.Value(name); ok {
+				ls = ls.addValue(name, v)
+			} else {
+				ls = ls.add(name, true)
+			}
+		default:
+			ls = ls.remove(name).add(name, false)
+		}
+	}
+	return ls
+}
+
+func stringArg(args promParser.Expressions, i int) string {
+	if i >= len(args) {
+		return ""
+	}
+	if s, ok := args[i].(*promParser.StringLiteral); ok {
+		return s.Val
+	}
+	return ""
+}
+
+// alwaysMatches returns true if label_replace regexp will match every value
+// of the source label.
+func alwaysMatches(regex, src string) bool {
+	re, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return false
+	}
+	if src == "" {
+		return re.MatchString("")
+	}
+	return regex == ".*" || regex == "(.*)"
+}
+
+func containsLabel(names []string, name string) bool {
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && names[i] == name
+}
+
+func appendLabel(names []string, name string) []string {
+	if name == "" || name == labels.MetricName || containsLabel(names, name) {
+		return names
+	}
+	// copy names first, it might share the backing array with another label set
+	out := make([]string, 0, len(names)+1)
+	out = append(out, names...)
+	out = append(out, name)
+	sort.Strings(out)
+	return out
+}
+
+func removeValues(values map[string]string, remove ...string) map[string]string {
+	var found bool
+	for _, name := range remove {
+		if _, ok := values[name]; ok {
+			found = true
+		}
+	}
+	if !found {
+		return values
+	}
+	kept := map[string]string{}
+	for k, v := range values {
+		kept[k] = v
+	}
+	for _, name := range remove {
+		delete(kept, name)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+func removeLabels(names []string, remove ...string) (kept []string) {
+	for _, name := range names {
+		var removed bool
+		for _, r := range remove {
+			if r == name {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
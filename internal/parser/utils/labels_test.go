@@ -0,0 +1,267 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/utils"
+)
+
+func TestOutputLabels(t *testing.T) {
+	type testCaseT struct {
+		expr   string
+		rules  string
+		output utils.LabelSet
+	}
+
+	testCases := []testCaseT{
+		{
+			expr:   "foo",
+			output: utils.LabelSet{},
+		},
+		{
+			expr:   `foo{job="bar", instance=~".+", env=""}`,
+			output: utils.LabelSet{Guaranteed: []string{"job"}, Excluded: []string{"env"}, Values: map[string]string{"job": "bar"}},
+		},
+		{
+			expr:   "1",
+			output: utils.LabelSet{Fixed: true},
+		},
+		{
+			expr:   "vector(1)",
+			output: utils.LabelSet{Fixed: true},
+		},
+		{
+			expr:   "time()",
+			output: utils.LabelSet{Fixed: true},
+		},
+		{
+			expr:   "sum(foo)",
+			output: utils.LabelSet{Fixed: true},
+		},
+		{
+			expr:   `sum(foo{job="bar"}) by(job, instance)`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance", "job"}, Guaranteed: []string{"job"}, Values: map[string]string{"job": "bar"}},
+		},
+		{
+			expr:   `sum(foo{job="bar"}) without(job, instance)`,
+			output: utils.LabelSet{Excluded: []string{"instance", "job"}},
+		},
+		{
+			expr:   `sum(sum(foo) by(job, instance)) without(instance)`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   "topk(5, sum(foo) by(job))",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   `count_values("version", sum(foo) by(job))`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"version"}, Guaranteed: []string{"version"}},
+		},
+		{
+			expr:   `rate(foo{job="bar"}[5m])`,
+			output: utils.LabelSet{Guaranteed: []string{"job"}, Values: map[string]string{"job": "bar"}},
+		},
+		{
+			expr:   `round(sum(rate(foo[5m])) by(job), 1)`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   `histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le, job))`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   `histogram_quantile(0.9, rate(foo_bucket[5m]))`,
+			output: utils.LabelSet{Excluded: []string{"le"}},
+		},
+		{
+			expr:   `absent(foo{job="bar", instance=~".+", env="prod", env="dev"})`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}, Guaranteed: []string{"job"}, Values: map[string]string{"job": "bar"}},
+		},
+		{
+			expr:   `absent_over_time(foo{job="bar"}[5m])`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}, Guaranteed: []string{"job"}, Values: map[string]string{"job": "bar"}},
+		},
+		{
+			expr:   `label_replace(sum(foo) by(job), "service", "$1", "job", "(.+)")`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job", "service"}},
+		},
+		{
+			expr:   `label_replace(sum(foo) by(job), "service", "api", "", "")`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job", "service"}, Guaranteed: []string{"service"}, Values: map[string]string{"service": "api"}},
+		},
+		{
+			expr:   `label_replace(foo, "service", "api", "job", ".*")`,
+			output: utils.LabelSet{Guaranteed: []string{"service"}, Values: map[string]string{"service": "api"}},
+		},
+		{
+			expr:   `label_replace(sum(foo{job="bar"}) by(job, instance), "job", "", "", "")`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance"}},
+		},
+		{
+			expr:   `label_replace(foo{job="bar"}, "job", "", "instance", ".*")`,
+			output: utils.LabelSet{Excluded: []string{"job"}},
+		},
+		{
+			expr:   `label_replace(foo{job="bar"}, "job", "", "instance", "(.+)")`,
+			output: utils.LabelSet{},
+		},
+		{
+			expr:   `label_join(sum(foo{job="bar"}) by(job, instance), "dst", ",", "job", "instance")`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"dst", "instance", "job"}, Guaranteed: []string{"dst", "job"}, Values: map[string]string{"job": "bar"}},
+		},
+		{
+			expr:   `label_join(sum(foo) by(instance), "dst", ",", "instance")`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"dst", "instance"}},
+		},
+		{
+			expr:   "sum(foo) by(job) > 0",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   "0 < sum(foo) by(job)",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   "-(1 + 2)",
+			output: utils.LabelSet{Fixed: true},
+		},
+		{
+			expr:   "sum(foo) by(job) and sum(bar) by(instance)",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   "sum(foo) by(job) unless sum(bar) by(instance)",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   `sum(foo{job="a"}) by(job) or sum(bar{job="b"}) by(job, instance)`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance", "job"}, Guaranteed: []string{"job"}},
+		},
+		{
+			expr:   `sum(foo{job="a"}) by(job) or sum(bar{job="a"}) by(job, instance)`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance", "job"}, Guaranteed: []string{"job"}, Values: map[string]string{"job": "a"}},
+		},
+		{
+			expr:   `sum(foo{job="a"}) without(instance) or bar{env=""}`,
+			output: utils.LabelSet{},
+		},
+		{
+			expr:   `sum(foo) without(instance, env) or sum(bar) by(job)`,
+			output: utils.LabelSet{Excluded: []string{"env", "instance"}},
+		},
+		{
+			expr:   "sum(foo) by(job, instance) / sum(bar) by(job, instance)",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance", "job"}},
+		},
+		{
+			expr:   `foo / on(job, instance) bar{job="a"}`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance", "job"}, Guaranteed: []string{"job"}, Values: map[string]string{"job": "a"}},
+		},
+		{
+			expr:   `foo > on(job) bar`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr:   "foo / ignoring(instance) bar",
+			output: utils.LabelSet{Excluded: []string{"instance"}},
+		},
+		{
+			expr:   `foo * on(instance) group_left(version) bar{version="1"}`,
+			output: utils.LabelSet{Guaranteed: []string{"version"}, Values: map[string]string{"version": "1"}},
+		},
+		{
+			expr:   `sum(foo) by(instance) * on(instance) group_left(version) bar`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance", "version"}},
+		},
+		{
+			expr:   `foo{version="1"} * on(instance) group_left(version) sum(bar) by(instance)`,
+			output: utils.LabelSet{Excluded: []string{"version"}},
+		},
+		{
+			expr:   `sum(bar) by(instance, version) * on(instance) group_right(version) foo`,
+			output: utils.LabelSet{},
+		},
+		{
+			expr:   "max_over_time(sum(foo) by(job)[5m:1m])",
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+		{
+			expr: "job:foo:sum",
+			rules: `
+- record: job:foo:sum
+  expr: sum(foo) by(job)
+  labels:
+    source: foo
+`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job", "source"}, Guaranteed: []string{"source"}, Values: map[string]string{"source": "foo"}},
+		},
+		{
+			expr: `job:foo:sum{env="prod"}`,
+			rules: `
+- record: job:foo:sum
+  expr: sum(foo) by(job, env)
+- record: job:foo:sum
+  expr: sum(bar) by(job, instance)
+  labels:
+    env: prod
+`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"env", "instance", "job"}, Guaranteed: []string{"env"}, Values: map[string]string{"env": "prod"}},
+		},
+		{
+			expr: "sum(job:foo:sum) without(job)",
+			rules: `
+- record: job:foo:sum
+  expr: sum(job:bar:sum) by(job, instance)
+- record: job:bar:sum
+  expr: sum(bar) by(job, instance, env)
+`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"instance"}},
+		},
+		{
+			expr: "foo",
+			rules: `
+- record: foo
+  expr: sum(bar) by(job)
+- record: bar
+  expr: foo
+`,
+			output: utils.LabelSet{Fixed: true, Possible: []string{"job"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			n, err := parser.DecodeExpr(tc.expr)
+			require.NoError(t, err)
+
+			var rules []parser.Rule
+			if tc.rules != "" {
+				rules, err = parser.NewParser().Parse([]byte(tc.rules))
+				require.NoError(t, err)
+			}
+
+			output := utils.OutputLabels(n, utils.NewRecordingRules(rules))
+			require.Equal(t, tc.output, output)
+		})
+	}
+}
+
+func TestLabelSet(t *testing.T) {
+	fixed := utils.LabelSet{Fixed: true, Possible: []string{"instance", "job"}, Guaranteed: []string{"job"}}
+	require.True(t, fixed.CanHave("job"))
+	require.True(t, fixed.CanHave("instance"))
+	require.False(t, fixed.CanHave("env"))
+	require.True(t, fixed.MustHave("job"))
+	require.False(t, fixed.MustHave("instance"))
+
+	open := utils.LabelSet{Guaranteed: []string{"job"}, Excluded: []string{"env"}}
+	require.True(t, open.CanHave("job"))
+	require.True(t, open.CanHave("instance"))
+	require.False(t, open.CanHave("env"))
+	require.True(t, open.MustHave("job"))
+	require.False(t, open.MustHave("instance"))
+}
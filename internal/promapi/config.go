@@ -39,6 +39,13 @@ func (p *Prometheus) Config(ctx context.Context) (*ConfigResult, error) {
 		return &cfg, nil
 	}
 
+	var cached ConfigResult
+	if p.disk.get(p.uri, key, key, &cached) {
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/status/config").Inc()
+		p.cache.Add(key, cached)
+		return &cached, nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
@@ -70,6 +77,7 @@ func (p *Prometheus) Config(ctx context.Context) (*ConfigResult, error) {
 
 	log.Debug().Str("key", key).Str("uri", p.uri).Msg("Config cache miss")
 	p.cache.Add(key, r)
+	p.disk.set(p.uri, key, key, r)
 
 	return &r, nil
 }
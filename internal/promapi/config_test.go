@@ -153,7 +153,7 @@ func TestConfig(t *testing.T) {
 		t.Run(strings.TrimPrefix(tc.prefix, "/"), func(t *testing.T) {
 			assert := assert.New(t)
 
-			prom := promapi.NewPrometheus("test", srv.URL+tc.prefix, tc.timeout)
+			prom := promapi.NewPrometheus("test", srv.URL+tc.prefix, nil, tc.timeout, nil)
 
 			wg := sync.WaitGroup{}
 			wg.Add(tc.runs)
@@ -0,0 +1,186 @@
+package promapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DiskCache stores query results on disk so they can be reused by other
+// pint processes. Each entry is a separate file and files are replaced
+// atomically, so it's safe to share the cache directory between pint
+// processes running at the same time.
+// Expired entries are removed when they are read and all entries older
+// than the highest TTL are removed the first time the cache is used.
+type DiskCache struct {
+	dir       string
+	ttls      map[string]time.Duration
+	pruneOnce sync.Once
+}
+
+// NewDiskCache creates a cache that will store results in given directory.
+// ttls maps API endpoint paths to the duration for which results are valid,
+// endpoints without a TTL or with a TTL that is <= 0 are not cached.
+func NewDiskCache(dir string, ttls map[string]time.Duration) *DiskCache {
+	return &DiskCache{dir: dir, ttls: ttls}
+}
+
+type diskCacheEntry struct {
+	URI      string          `json:"uri"`
+	Endpoint string          `json:"endpoint"`
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+}
+
+func (dc *DiskCache) path(uri, endpoint, key string) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{uri, endpoint, key}, "\n")))
+	return filepath.Join(dc.dir, hex.EncodeToString(h[:])+".json")
+}
+
+func (dc *DiskCache) get(uri, endpoint, key string, v interface{}) bool {
+	if dc == nil || dc.ttls[endpoint] <= 0 {
+		return false
+	}
+	dc.pruneOnce.Do(dc.prune)
+
+	path := dc.path(uri, endpoint, key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if time.Since(info.ModTime()) > dc.ttls[endpoint] {
+		log.Debug().Str("uri", uri).Str("endpoint", endpoint).Str("path", path).Msg("Disk cache entry expired")
+		dc.remove(path)
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var entry diskCacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to decode disk cache entry")
+		return false
+	}
+	if entry.URI != uri || entry.Endpoint != endpoint || entry.Key != key {
+		return false
+	}
+	if err = json.Unmarshal(entry.Value, v); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to decode disk cache entry")
+		return false
+	}
+
+	log.Debug().Str("uri", uri).Str("endpoint", endpoint).Str("path", path).Msg("Disk cache hit")
+	return true
+}
+
+func (dc *DiskCache) set(uri, endpoint, key string, v interface{}) {
+	if dc == nil || dc.ttls[endpoint] <= 0 {
+		return
+	}
+	dc.pruneOnce.Do(dc.prune)
+
+	if err := dc.write(dc.path(uri, endpoint, key), uri, endpoint, key, v); err != nil {
+		log.Warn().Err(err).Str("uri", uri).Str("endpoint", endpoint).Msg("Failed to write disk cache entry")
+	}
+}
+
+func (dc *DiskCache) write(path, uri, endpoint, key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(diskCacheEntry{URI: uri, Endpoint: endpoint, Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dc.dir, 0o755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first and then rename it, so other processes
+	// will never see a partially written entry
+	f, err := os.CreateTemp(dc.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to save disk cache entry: %w", err)
+	}
+	return nil
+}
+
+// prune removes all cache files that are too old to be used for any endpoint.
+// Only files created by the cache are removed, anything else stored
+// in the cache directory is left untouched.
+func (dc *DiskCache) prune() {
+	var maxTTL time.Duration
+	for _, ttl := range dc.ttls {
+		if ttl > maxTTL {
+			maxTTL = ttl
+		}
+	}
+
+	entries, err := os.ReadDir(dc.dir)
+	if err != nil {
+		return
+	}
+
+	var removed int
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isDiskCacheFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) <= maxTTL {
+			continue
+		}
+		if dc.remove(filepath.Join(dc.dir, e.Name())) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Debug().Str("dir", dc.dir).Int("removed", removed).Msg("Removed expired disk cache entries")
+	}
+}
+
+func (dc *DiskCache) remove(path string) bool {
+	// other pint processes might be removing the same file
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to remove expired disk cache entry")
+		return false
+	}
+	return true
+}
+
+func isDiskCacheFile(name string) bool {
+	if strings.HasPrefix(name, ".tmp-") {
+		return true
+	}
+	if !strings.HasSuffix(name, ".json") {
+		return false
+	}
+	b, err := hex.DecodeString(strings.TrimSuffix(name, ".json"))
+	return err == nil && len(b) == sha256.Size
+}
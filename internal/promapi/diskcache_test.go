@@ -0,0 +1,162 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestDiskCache(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/status/config":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 30s\n"}}`))
+		case "/api/v1/query":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[1614859502.068,"1"]}]}}`))
+		case "/api/v1/query_range":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"foo"},"values":[[1614859502.068,"1"]]}]}}`))
+		case "/api/v1/metadata":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"up":[{"type":"gauge","help":"Target is up","unit":""}]}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	ttls := map[string]time.Duration{
+		"/api/v1/query":         time.Hour,
+		"/api/v1/query_range":   time.Hour,
+		"/api/v1/status/config": time.Hour,
+		"/api/v1/metadata":      0,
+	}
+
+	run := func() {
+		prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+		prom.SetDiskCache(promapi.NewDiskCache(dir, ttls))
+
+		qr, err := prom.Query(context.Background(), "up")
+		require.NoError(t, err)
+		require.Len(t, qr.Series, 1)
+		require.Equal(t, model.LabelValue("foo"), qr.Series[0].Metric["job"])
+
+		rqr, err := prom.RangeQuery(context.Background(), "up", time.Hour, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, rqr.Samples, 1)
+		require.Len(t, rqr.Samples[0].Values, 1)
+
+		cfg, err := prom.Config(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, time.Second*30, cfg.Config.Global.ScrapeInterval)
+
+		metadata, err := prom.Metadata(context.Background(), "up")
+		require.NoError(t, err)
+		require.Len(t, metadata.Metadata, 1)
+	}
+
+	run()
+	require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	// only metadata is not cached
+	run()
+	require.Equal(t, int32(5), atomic.LoadInt32(&requests))
+
+	// expired and broken entries are ignored
+	old := time.Now().Add(time.Hour * -2)
+	for i, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if i == 0 {
+			require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+		}
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+	run()
+	require.Equal(t, int32(9), atomic.LoadInt32(&requests))
+
+	run()
+	require.Equal(t, int32(10), atomic.LoadInt32(&requests))
+}
+
+func TestDiskCacheWriteError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.WriteFile(path, []byte("not a directory"), 0o644))
+
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetDiskCache(promapi.NewDiskCache(path, map[string]time.Duration{"/api/v1/query": time.Hour}))
+
+	qr, err := prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Empty(t, qr.Series)
+}
+
+func TestDiskCachePrune(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	old := time.Now().Add(time.Hour * -3)
+	files := map[string]bool{
+		"0000000000000000000000000000000000000000000000000000000000000000.json": false,
+		".tmp-123":   false,
+		"notes.json": true,
+		"notes.txt":  true,
+	}
+	for name := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+	fresh := "1111111111111111111111111111111111111111111111111111111111111111.json"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fresh), []byte("{}"), 0o644))
+	files[fresh] = true
+
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetDiskCache(promapi.NewDiskCache(dir, map[string]time.Duration{
+		"/api/v1/query":       time.Hour,
+		"/api/v1/query_range": time.Hour * 2,
+	}))
+
+	_, err := prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+
+	for name, exists := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists {
+			require.NoError(t, err, name)
+		} else {
+			require.True(t, os.IsNotExist(err), name)
+		}
+	}
+}
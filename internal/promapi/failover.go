@@ -51,6 +51,12 @@ func (fg *FailoverGroup) ClearCache() {
 	}
 }
 
+func (fg *FailoverGroup) SetSession(s *Session) {
+	for _, prom := range fg.servers {
+		prom.SetSession(s)
+	}
+}
+
 func (fg *FailoverGroup) Config(ctx context.Context) (cfg *ConfigResult, err error) {
 	var uri string
 	for _, prom := range fg.servers {
@@ -95,3 +101,63 @@ func (fg *FailoverGroup) RangeQuery(ctx context.Context, expr string, lookback,
 	}
 	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
 }
+
+func (fg *FailoverGroup) Metadata(ctx context.Context, metric string) (metadata *MetadataResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.uri
+		metadata, err = prom.Metadata(ctx, metric)
+		if err == nil {
+			return
+		}
+		if !IsUnavailableError(err) {
+			return metadata, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) Series(ctx context.Context, matches []string, lookback time.Duration) (sr *SeriesResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.uri
+		sr, err = prom.Series(ctx, matches, lookback)
+		if err == nil {
+			return
+		}
+		if !IsUnavailableError(err) {
+			return sr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) Labels(ctx context.Context, matches []string, lookback time.Duration) (lr *LabelsResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.uri
+		lr, err = prom.Labels(ctx, matches, lookback)
+		if err == nil {
+			return
+		}
+		if !IsUnavailableError(err) {
+			return lr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) LabelValues(ctx context.Context, label string, matches []string, lookback time.Duration) (lvr *LabelValuesResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.uri
+		lvr, err = prom.LabelValues(ctx, label, matches, lookback)
+		if err == nil {
+			return
+		}
+		if !IsUnavailableError(err) {
+			return lvr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
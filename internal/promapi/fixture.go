@@ -0,0 +1,607 @@
+package promapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/promql"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FixtureURIPrefix = "file://"
+
+	openMetricsContentType = "application/openmetrics-text"
+	openMetricsEOF         = "# EOF"
+)
+
+// FixturePath returns the path of a fixture file if given URI is
+// using the file:// scheme.
+func FixturePath(uri string) (string, bool) {
+	if !strings.HasPrefix(uri, FixtureURIPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(uri, FixtureURIPrefix), true
+}
+
+// SeriesFixture is a fixture file using the same format for series
+// as promtool rule unit tests.
+type SeriesFixture struct {
+	Interval    model.Duration      `yaml:"interval"`
+	InputSeries []SeriesFixtureItem `yaml:"input_series"`
+	Config      PrometheusConfig    `yaml:"config"`
+}
+
+type SeriesFixtureItem struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+type fixtureSample struct {
+	t int64
+	v float64
+}
+
+func (s fixtureSample) T() int64 {
+	return s.t
+}
+
+func (s fixtureSample) V() float64 {
+	return s.v
+}
+
+type fixtureSeries struct {
+	lset    labels.Labels
+	samples []tsdbutil.Sample
+}
+
+// fixtureData holds all series loaded from a fixture file in memory
+// and implements storage.Queryable so it can be used by the PromQL engine.
+type fixtureData struct {
+	series   []fixtureSeries
+	metadata map[string][]v1.Metadata
+	config   PrometheusConfig
+}
+
+// loadFixture reads all series from a fixture file. Files with .yml or .yaml
+// extension are parsed as input_series fixtures, all other files are parsed
+// as OpenMetrics or Prometheus text format.
+// Fixtures are shifted in time so the newest sample is at given time,
+// this way every query returns the same results no matter when it's run.
+func loadFixture(path string, now time.Time) (*fixtureData, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fd *fixtureData
+	switch filepath.Ext(path) {
+	case ".yml", ".yaml":
+		fd, err = parseSeriesFixture(content)
+	default:
+		fd, err = parseTextFixture(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var maxt int64 = math.MinInt64
+	for _, s := range fd.series {
+		if len(s.samples) > 0 && s.samples[len(s.samples)-1].T() > maxt {
+			maxt = s.samples[len(s.samples)-1].T()
+		}
+	}
+	offset := now.UnixMilli() - maxt
+	for _, s := range fd.series {
+		for i, smpl := range s.samples {
+			s.samples[i] = fixtureSample{t: smpl.T() + offset, v: smpl.V()}
+		}
+	}
+
+	sort.Slice(fd.series, func(i, j int) bool {
+		return labels.Compare(fd.series[i].lset, fd.series[j].lset) < 0
+	})
+
+	return fd, nil
+}
+
+func parseSeriesFixture(content []byte) (*fixtureData, error) {
+	var sf SeriesFixture
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&sf); err != nil {
+		return nil, err
+	}
+	if sf.Interval == 0 {
+		sf.Interval = model.Duration(time.Minute)
+	}
+	step := time.Duration(sf.Interval).Milliseconds()
+
+	fd := fixtureData{metadata: map[string][]v1.Metadata{}, config: sf.Config}
+	for _, is := range sf.InputSeries {
+		lset, values, err := promParser.ParseSeriesDesc(is.Series + " " + is.Values)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series %q: %w", is.Series, err)
+		}
+		fs := fixtureSeries{lset: lset}
+		for i, v := range values {
+			if v.Omitted {
+				continue
+			}
+			fs.samples = append(fs.samples, fixtureSample{t: int64(i) * step, v: v.Value})
+		}
+		fd.series = append(fd.series, fs)
+	}
+	return &fd, nil
+}
+
+func parseTextFixture(content []byte) (*fixtureData, error) {
+	contentType := ""
+	if bytes.Contains(content, []byte(openMetricsEOF)) {
+		contentType = openMetricsContentType
+	}
+	p, err := textparse.New(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	fd := fixtureData{metadata: map[string][]v1.Metadata{}}
+	meta := map[string]*v1.Metadata{}
+	metaFor := func(name []byte) *v1.Metadata {
+		m, ok := meta[string(name)]
+		if !ok {
+			m = &v1.Metadata{}
+			meta[string(name)] = m
+		}
+		return m
+	}
+
+	// samples without a timestamp are all using the same one,
+	// newer than any other sample
+	type samplePos struct {
+		series, sample int
+	}
+	var maxt int64
+	var noTimestamp []samplePos
+	seriesIndex := map[uint64]int{}
+	for {
+		entry, err := p.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch entry {
+		case textparse.EntryType:
+			name, typ := p.Type()
+			metaFor(name).Type = v1.MetricType(typ)
+		case textparse.EntryHelp:
+			name, help := p.Help()
+			metaFor(name).Help = string(help)
+		case textparse.EntryUnit:
+			name, unit := p.Unit()
+			metaFor(name).Unit = string(unit)
+		case textparse.EntrySeries:
+			_, ts, v := p.Series()
+			var lset labels.Labels
+			p.Metric(&lset)
+			idx, ok := seriesIndex[lset.Hash()]
+			if !ok {
+				idx = len(fd.series)
+				seriesIndex[lset.Hash()] = idx
+				fd.series = append(fd.series, fixtureSeries{lset: lset})
+			}
+			if ts == nil {
+				noTimestamp = append(noTimestamp, samplePos{series: idx, sample: len(fd.series[idx].samples)})
+				fd.series[idx].samples = append(fd.series[idx].samples, fixtureSample{v: v})
+				continue
+			}
+			if *ts > maxt {
+				maxt = *ts
+			}
+			fd.series[idx].samples = append(fd.series[idx].samples, fixtureSample{t: *ts, v: v})
+		}
+	}
+
+	for _, pos := range noTimestamp {
+		s := fd.series[pos.series].samples[pos.sample]
+		fd.series[pos.series].samples[pos.sample] = fixtureSample{t: maxt, v: s.V()}
+	}
+
+	for _, s := range fd.series {
+		sort.SliceStable(s.samples, func(i, j int) bool {
+			return s.samples[i].T() < s.samples[j].T()
+		})
+	}
+
+	for name, m := range meta {
+		fd.metadata[name] = []v1.Metadata{*m}
+	}
+
+	return &fd, nil
+}
+
+func (fd *fixtureData) Querier(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+	return &fixtureQuerier{fd: fd, mint: mint, maxt: maxt}, nil
+}
+
+func (fd *fixtureData) matching(matchers []*labels.Matcher) (series []fixtureSeries) {
+	for _, s := range fd.series {
+		ok := true
+		for _, m := range matchers {
+			if !m.Matches(s.lset.Get(m.Name)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			series = append(series, s)
+		}
+	}
+	return series
+}
+
+type fixtureQuerier struct {
+	fd   *fixtureData
+	mint int64
+	maxt int64
+}
+
+func (fq *fixtureQuerier) Select(_ bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	mint, maxt := fq.mint, fq.maxt
+	if hints != nil {
+		mint, maxt = hints.Start, hints.End
+	}
+
+	ss := fixtureSeriesSet{idx: -1}
+	for _, s := range fq.fd.matching(matchers) {
+		var samples []tsdbutil.Sample
+		for _, smpl := range s.samples {
+			if smpl.T() >= mint && smpl.T() <= maxt {
+				samples = append(samples, smpl)
+			}
+		}
+		if len(samples) > 0 {
+			ss.series = append(ss.series, storage.NewListSeries(s.lset, samples))
+		}
+	}
+	return &ss
+}
+
+func (fq *fixtureQuerier) LabelValues(name string, matchers ...*labels.Matcher) ([]string, storage.Warnings, error) {
+	values := map[string]struct{}{}
+	for _, s := range fq.fd.matching(matchers) {
+		if v := s.lset.Get(name); v != "" {
+			values[v] = struct{}{}
+		}
+	}
+	return sortedKeys(values), nil, nil
+}
+
+func (fq *fixtureQuerier) LabelNames(matchers ...*labels.Matcher) ([]string, storage.Warnings, error) {
+	names := map[string]struct{}{}
+	for _, s := range fq.fd.matching(matchers) {
+		for _, l := range s.lset {
+			names[l.Name] = struct{}{}
+		}
+	}
+	return sortedKeys(names), nil, nil
+}
+
+func (fq *fixtureQuerier) Close() error {
+	return nil
+}
+
+type fixtureSeriesSet struct {
+	series []storage.Series
+	idx    int
+}
+
+func (ss *fixtureSeriesSet) Next() bool {
+	ss.idx++
+	return ss.idx < len(ss.series)
+}
+
+func (ss *fixtureSeriesSet) At() storage.Series {
+	return ss.series[ss.idx]
+}
+
+func (ss *fixtureSeriesSet) Err() error {
+	return nil
+}
+
+func (ss *fixtureSeriesSet) Warnings() storage.Warnings {
+	return nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fixtureClient implements api.Client and answers Prometheus API requests
+// using series loaded from a fixture file instead of sending them to
+// a Prometheus server.
+type fixtureClient struct {
+	path   string
+	engine *promql.Engine
+
+	once sync.Once
+	data *fixtureData
+	err  error
+}
+
+func newFixtureClient(path string, timeout time.Duration) *fixtureClient {
+	return &fixtureClient{
+		path: path,
+		engine: promql.NewEngine(promql.EngineOpts{
+			MaxSamples:           50000000,
+			Timeout:              timeout,
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
+		}),
+	}
+}
+
+func (fc *fixtureClient) load() (*fixtureData, error) {
+	fc.once.Do(func() {
+		fc.data, fc.err = loadFixture(fc.path, time.Now())
+		if fc.err == nil {
+			log.Debug().Str("path", fc.path).Int("series", len(fc.data.series)).Msg("Loaded Prometheus fixture")
+		}
+	})
+	return fc.data, fc.err
+}
+
+func (fc *fixtureClient) URL(ep string, args map[string]string) *url.URL {
+	p := ep
+	for arg, val := range args {
+		p = strings.ReplaceAll(p, ":"+arg, val)
+	}
+	return &url.URL{Scheme: "file", Path: p}
+}
+
+func (fc *fixtureClient) Do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	fd, err := fc.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = req.ParseForm(); err != nil {
+		return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+	}
+
+	switch req.URL.Path {
+	case "/api/v1/query":
+		ts, err := parseFixtureTime(req.Form.Get("time"), time.Now())
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		q, err := fc.engine.NewInstantQuery(fd, nil, req.Form.Get("query"), ts)
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		return fixtureQueryResponse(ctx, q)
+	case "/api/v1/query_range":
+		start, err := parseFixtureTime(req.Form.Get("start"), time.Now())
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		end, err := parseFixtureTime(req.Form.Get("end"), time.Now())
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		step, err := parseFixtureDuration(req.Form.Get("step"))
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		q, err := fc.engine.NewRangeQuery(fd, nil, req.Form.Get("query"), start, end, step)
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		return fixtureQueryResponse(ctx, q)
+	case "/api/v1/status/config":
+		cfg, err := yaml.Marshal(fd.config)
+		if err != nil {
+			return fixtureResponse(http.StatusInternalServerError, nil, v1.ErrServer, err)
+		}
+		return fixtureResponse(http.StatusOK, v1.ConfigResult{YAML: string(cfg)}, "", nil)
+	case "/api/v1/metadata":
+		metadata := fd.metadata
+		if metric := req.Form.Get("metric"); metric != "" {
+			metadata = map[string][]v1.Metadata{}
+			if m, ok := fd.metadata[metric]; ok {
+				metadata[metric] = m
+			}
+		}
+		return fixtureResponse(http.StatusOK, metadata, "", nil)
+	case "/api/v1/series":
+		series, err := fixtureSeriesForRequest(fd, req)
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		result := make([]map[string]string, 0, len(series))
+		for _, lset := range series {
+			result = append(result, lset.Map())
+		}
+		return fixtureResponse(http.StatusOK, result, "", nil)
+	case "/api/v1/labels":
+		series, err := fixtureSeriesForRequest(fd, req)
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		names := map[string]struct{}{}
+		for _, lset := range series {
+			for _, l := range lset {
+				names[l.Name] = struct{}{}
+			}
+		}
+		return fixtureResponse(http.StatusOK, sortedKeys(names), "", nil)
+	}
+
+	if name := strings.TrimPrefix(req.URL.Path, "/api/v1/label/"); name != req.URL.Path && strings.HasSuffix(name, "/values") {
+		name = strings.TrimSuffix(name, "/values")
+		series, err := fixtureSeriesForRequest(fd, req)
+		if err != nil {
+			return fixtureResponse(http.StatusBadRequest, nil, v1.ErrBadData, err)
+		}
+		values := map[string]struct{}{}
+		for _, lset := range series {
+			if v := lset.Get(name); v != "" {
+				values[v] = struct{}{}
+			}
+		}
+		return fixtureResponse(http.StatusOK, sortedKeys(values), "", nil)
+	}
+
+	return fixtureResponse(http.StatusNotFound, nil, v1.ErrBadData, fmt.Errorf("%s is not supported when using fixture files", req.URL.Path))
+}
+
+// fixtureSeriesForRequest returns all series matching any of match[] selectors
+// from the request, with at least one sample between start and end.
+func fixtureSeriesForRequest(fd *fixtureData, req *http.Request) ([]labels.Labels, error) {
+	now := time.Now()
+	start, err := parseFixtureTime(req.Form.Get("start"), time.Unix(0, 0))
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseFixtureTime(req.Form.Get("end"), now)
+	if err != nil {
+		return nil, err
+	}
+
+	q, _ := fd.Querier(req.Context(), start.UnixMilli(), end.UnixMilli())
+	defer q.Close()
+
+	matches := req.Form["match[]"]
+	if len(matches) == 0 {
+		matches = []string{`{__name__=~".+"}`}
+	}
+
+	seen := map[uint64]struct{}{}
+	var series []labels.Labels
+	for _, m := range matches {
+		matchers, err := promParser.ParseMetricSelector(m)
+		if err != nil {
+			return nil, err
+		}
+		ss := q.Select(false, nil, matchers...)
+		for ss.Next() {
+			lset := ss.At().Labels()
+			if _, ok := seen[lset.Hash()]; ok {
+				continue
+			}
+			seen[lset.Hash()] = struct{}{}
+			series = append(series, lset)
+		}
+	}
+	return series, nil
+}
+
+func fixtureQueryResponse(ctx context.Context, q promql.Query) (*http.Response, []byte, error) {
+	defer q.Close()
+
+	res := q.Exec(ctx)
+	if res.Err != nil {
+		var (
+			errTimeout  promql.ErrQueryTimeout
+			errCanceled promql.ErrQueryCanceled
+		)
+		switch {
+		case errors.As(res.Err, &errTimeout):
+			return fixtureResponse(http.StatusServiceUnavailable, nil, v1.ErrTimeout, res.Err)
+		case errors.As(res.Err, &errCanceled):
+			return fixtureResponse(http.StatusServiceUnavailable, nil, v1.ErrCanceled, res.Err)
+		default:
+			return fixtureResponse(http.StatusUnprocessableEntity, nil, v1.ErrExec, res.Err)
+		}
+	}
+
+	return fixtureResponse(http.StatusOK, struct {
+		ResultType promParser.ValueType `json:"resultType"`
+		Result     promParser.Value     `json:"result"`
+	}{
+		ResultType: res.Value.Type(),
+		Result:     res.Value,
+	}, "", nil)
+}
+
+func fixtureResponse(code int, data interface{}, errType v1.ErrorType, err error) (*http.Response, []byte, error) {
+	resp := struct {
+		Status    string       `json:"status"`
+		Data      interface{}  `json:"data,omitempty"`
+		ErrorType v1.ErrorType `json:"errorType,omitempty"`
+		Error     string       `json:"error,omitempty"`
+	}{
+		Status: "success",
+		Data:   data,
+	}
+	if err != nil {
+		resp.Status = "error"
+		resp.ErrorType = errType
+		resp.Error = err.Error()
+	}
+
+	body, jerr := json.Marshal(resp)
+	if jerr != nil {
+		return nil, nil, jerr
+	}
+	return &http.Response{
+		StatusCode: code,
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, body, nil
+}
+
+func parseFixtureTime(s string, fallback time.Time) (time.Time, error) {
+	if s == "" {
+		return fallback, nil
+	}
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, ns := math.Modf(t)
+		return time.Unix(int64(sec), int64(math.Round(ns*1000))*int64(time.Millisecond)).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+func parseFixtureDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(d * float64(time.Second)), nil
+	}
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
@@ -0,0 +1,178 @@
+package promapi_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestFixturePath(t *testing.T) {
+	path, ok := promapi.FixturePath("file://fixtures/prod.om")
+	require.True(t, ok)
+	require.Equal(t, "fixtures/prod.om", path)
+
+	path, ok = promapi.FixturePath("file:///tmp/prod.om")
+	require.True(t, ok)
+	require.Equal(t, "/tmp/prod.om", path)
+
+	_, ok = promapi.FixturePath("http://localhost:9090")
+	require.False(t, ok)
+}
+
+func TestFixtureOpenMetrics(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prom.om")
+	require.NoError(t, os.WriteFile(path, []byte(`# TYPE http_requests counter
+# HELP http_requests Total number of requests.
+http_requests_total{job="a"} 1 1000
+http_requests_total{job="a"} 5 1060
+http_requests_total{job="b"} 3 1060
+# TYPE up gauge
+up{job="a"} 1
+up{job="b"} 0
+# EOF
+`), 0o644))
+
+	prom := promapi.NewPrometheus("prom", "file://"+path, nil, time.Second, nil)
+
+	qr, err := prom.Query(context.Background(), "sum(http_requests_total)")
+	require.NoError(t, err)
+	require.Equal(t, "file://"+path, qr.URI)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.SampleValue(8), qr.Series[0].Value)
+
+	qr, err = prom.Query(context.Background(), "up == 0")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.LabelValue("b"), qr.Series[0].Metric["job"])
+
+	qr, err = prom.Query(context.Background(), "rate(http_requests_total[5m])")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.LabelValue("a"), qr.Series[0].Metric["job"])
+
+	_, err = prom.Query(context.Background(), "sum(foo")
+	require.EqualError(t, err, "bad_data: 1:8: parse error: unclosed left parenthesis")
+
+	metadata, err := prom.Metadata(context.Background(), "http_requests")
+	require.NoError(t, err)
+	require.Equal(t, []v1.Metadata{{Type: "counter", Help: "Total number of requests."}}, metadata.Metadata)
+
+	metadata, err = prom.Metadata(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Empty(t, metadata.Metadata)
+
+	cfg, err := prom.Config(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, cfg.Config.Global.ScrapeInterval)
+}
+
+func TestFixtureSeries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prom.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`interval: 1m
+config:
+  global:
+    scrape_interval: 30s
+    external_labels:
+      cluster: dev
+input_series:
+- series: up{job="a"}
+  values: 1x10
+- series: up{job="b"}
+  values: 0 _x9 1
+`), 0o644))
+
+	prom := promapi.NewPrometheus("prom", "file://"+path, nil, time.Second, nil)
+
+	qr, err := prom.Query(context.Background(), "count(up == 1)")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.SampleValue(2), qr.Series[0].Value)
+
+	rqr, err := prom.RangeQuery(context.Background(), `up{job="a"}`, time.Minute*15, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, rqr.Samples, 1)
+	require.Len(t, rqr.Samples[0].Values, 11)
+
+	rqr, err = prom.RangeQuery(context.Background(), `up{job="b"}`, time.Minute*15, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, rqr.Samples, 1)
+	require.Len(t, rqr.Samples[0].Values, 6)
+
+	sr, err := prom.Series(context.Background(), []string{`up{job=~"a|c"}`}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []model.LabelSet{{"__name__": "up", "job": "a"}}, sr.Series)
+
+	sr, err = prom.Series(context.Background(), []string{`up{job="b"}`}, time.Minute*5)
+	require.NoError(t, err)
+	require.Equal(t, []model.LabelSet{{"__name__": "up", "job": "b"}}, sr.Series)
+
+	sr, err = prom.Series(context.Background(), []string{`up{job="b"}`}, time.Minute*30)
+	require.NoError(t, err)
+	require.Len(t, sr.Series, 1)
+
+	lr, err := prom.Labels(context.Background(), []string{"up"}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []string{"__name__", "job"}, lr.Names)
+
+	lr, err = prom.Labels(context.Background(), []string{"foo"}, time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, lr.Names)
+
+	lvr, err := prom.LabelValues(context.Background(), "job", []string{"up"}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, lvr.Values)
+
+	_, err = prom.Series(context.Background(), []string{"up{"}, time.Hour)
+	require.Error(t, err)
+	require.False(t, promapi.IsUnavailableError(err))
+
+	cfg, err := prom.Config(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, time.Second*30, cfg.Config.Global.ScrapeInterval)
+	require.Equal(t, map[string]string{"cluster": "dev"}, cfg.Config.Global.ExternalLabels)
+}
+
+func TestFixtureErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	prom := promapi.NewPrometheus("prom", "file://"+filepath.Join(dir, "missing.om"), nil, time.Second, nil)
+	_, err := prom.Query(context.Background(), "up")
+	require.Error(t, err)
+	require.True(t, promapi.IsUnavailableError(err))
+
+	path := filepath.Join(dir, "broken.yml")
+	require.NoError(t, os.WriteFile(path, []byte("input_series:\n- series: up{\n  values: 1\n"), 0o644))
+	prom = promapi.NewPrometheus("prom", "file://"+path, nil, time.Second, nil)
+	_, err = prom.Query(context.Background(), "up")
+	require.EqualError(t, err, `failed to parse `+path+`: invalid series "up{": 1:5: parse error: unexpected character inside braces: '1'`)
+}
+
+func TestFixtureDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prom.om")
+	cacheDir := filepath.Join(dir, "cache")
+
+	query := func(content string) model.SampleValue {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		prom := promapi.NewPrometheus("prom", "file://"+path, nil, time.Second, nil)
+		prom.SetDiskCache(promapi.NewDiskCache(cacheDir, map[string]time.Duration{"/api/v1/query": time.Hour}))
+		qr, err := prom.Query(context.Background(), "up")
+		require.NoError(t, err)
+		require.Len(t, qr.Series, 1)
+		return qr.Series[0].Value
+	}
+
+	require.Equal(t, model.SampleValue(1), query("up 1\n# EOF\n"))
+	require.Equal(t, model.SampleValue(0), query("up 0\n# EOF\n"))
+	require.NoDirExists(t, cacheDir)
+}
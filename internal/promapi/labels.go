@@ -0,0 +1,124 @@
+package promapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/cloudflare/pint/internal/output"
+)
+
+type LabelsResult struct {
+	URI   string
+	Names []string
+	Start time.Time
+	End   time.Time
+}
+
+func (p *Prometheus) Labels(ctx context.Context, matches []string, lookback time.Duration) (*LabelsResult, error) {
+	log.Debug().
+		Str("uri", p.uri).
+		Strs("matches", matches).
+		Str("lookback", output.HumanizeDuration(lookback)).
+		Msg("Query Prometheus label names")
+
+	key := strings.Join(append([]string{"/api/v1/labels", lookback.String()}, matches...), "\n")
+	p.lock.lock(key)
+	defer p.lock.unlock(key)
+
+	if v, ok := p.cache.Get(key); ok {
+		log.Debug().Str("key", key).Str("uri", p.uri).Msg("Labels cache hit")
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/labels").Inc()
+		r := v.(LabelsResult)
+		return &r, nil
+	}
+
+	var cached LabelsResult
+	if p.disk.get(p.uri, "/api/v1/labels", key, &cached) {
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/labels").Inc()
+		p.cache.Add(key, cached)
+		return &cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	prometheusQueriesTotal.WithLabelValues(p.name, "/api/v1/labels").Inc()
+	end := time.Now()
+	start := end.Add(lookback * -1)
+	names, _, err := p.api.LabelNames(ctx, matches, start, end)
+	if err != nil {
+		log.Error().Err(err).Str("uri", p.uri).Strs("matches", matches).Msg("Failed to query Prometheus label names")
+		prometheusQueryErrorsTotal.WithLabelValues(p.name, "/api/v1/labels", errReason(err)).Inc()
+		return nil, fmt.Errorf("failed to query Prometheus label names: %w", err)
+	}
+
+	r := LabelsResult{URI: p.uri, Names: names, Start: start, End: end}
+
+	log.Debug().Str("key", key).Str("uri", p.uri).Int("labels", len(names)).Msg("Labels cache miss")
+	p.cache.Add(key, r)
+	p.disk.set(p.uri, "/api/v1/labels", key, r)
+
+	return &r, nil
+}
+
+type LabelValuesResult struct {
+	URI    string
+	Values []string
+	Start  time.Time
+	End    time.Time
+}
+
+func (p *Prometheus) LabelValues(ctx context.Context, label string, matches []string, lookback time.Duration) (*LabelValuesResult, error) {
+	log.Debug().
+		Str("uri", p.uri).
+		Str("label", label).
+		Strs("matches", matches).
+		Str("lookback", output.HumanizeDuration(lookback)).
+		Msg("Query Prometheus label values")
+
+	key := strings.Join(append([]string{"/api/v1/label/values", label, lookback.String()}, matches...), "\n")
+	p.lock.lock(key)
+	defer p.lock.unlock(key)
+
+	if v, ok := p.cache.Get(key); ok {
+		log.Debug().Str("key", key).Str("uri", p.uri).Msg("Label values cache hit")
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/label/values").Inc()
+		r := v.(LabelValuesResult)
+		return &r, nil
+	}
+
+	var cached LabelValuesResult
+	if p.disk.get(p.uri, "/api/v1/label/values", key, &cached) {
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/label/values").Inc()
+		p.cache.Add(key, cached)
+		return &cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	prometheusQueriesTotal.WithLabelValues(p.name, "/api/v1/label/values").Inc()
+	end := time.Now()
+	start := end.Add(lookback * -1)
+	values, _, err := p.api.LabelValues(ctx, label, matches, start, end)
+	if err != nil {
+		log.Error().Err(err).Str("uri", p.uri).Str("label", label).Strs("matches", matches).Msg("Failed to query Prometheus label values")
+		prometheusQueryErrorsTotal.WithLabelValues(p.name, "/api/v1/label/values", errReason(err)).Inc()
+		return nil, fmt.Errorf("failed to query Prometheus label values: %w", err)
+	}
+
+	r := LabelValuesResult{URI: p.uri, Start: start, End: end}
+	for _, v := range values {
+		r.Values = append(r.Values, string(v))
+	}
+
+	log.Debug().Str("key", key).Str("uri", p.uri).Int("values", len(values)).Msg("Label values cache miss")
+	p.cache.Add(key, r)
+	p.disk.set(p.uri, "/api/v1/label/values", key, r)
+
+	return &r, nil
+}
@@ -0,0 +1,79 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestLabels(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path + "?" + r.URL.Query().Get("match[]") {
+		case "/api/v1/labels?up":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":["__name__","instance","job"]}`))
+		case "/api/v1/labels?missing":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+		case "/api/v1/label/job/values?up":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":["a","b"]}`))
+		case "/api/v1/label/job/values?missing":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+		case "/api/v1/labels?error", "/api/v1/label/job/values?error":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		default:
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled match"}`))
+		}
+	}))
+	defer srv.Close()
+
+	prom := promapi.NewPrometheus("test", srv.URL, nil, time.Second, nil)
+
+	for i := 0; i < 5; i++ {
+		lr, err := prom.Labels(context.Background(), []string{"up"}, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, srv.URL, lr.URI)
+		require.Equal(t, []string{"__name__", "instance", "job"}, lr.Names)
+		require.Equal(t, time.Hour, lr.End.Sub(lr.Start))
+
+		lvr, err := prom.LabelValues(context.Background(), "job", []string{"up"}, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, srv.URL, lvr.URI)
+		require.Equal(t, []string{"a", "b"}, lvr.Values)
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	lr, err := prom.Labels(context.Background(), []string{"missing"}, time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, lr.Names)
+
+	lvr, err := prom.LabelValues(context.Background(), "job", []string{"missing"}, time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, lvr.Values)
+
+	_, err = prom.Labels(context.Background(), []string{"error"}, time.Hour)
+	require.EqualError(t, err, "failed to query Prometheus label names: server_error: server error: 500")
+	require.True(t, promapi.IsUnavailableError(err))
+
+	_, err = prom.LabelValues(context.Background(), "job", []string{"error"}, time.Hour)
+	require.EqualError(t, err, "failed to query Prometheus label values: server_error: server error: 500")
+	require.True(t, promapi.IsUnavailableError(err))
+
+	_, err = prom.LabelValues(context.Background(), "job", []string{"foo"}, time.Hour)
+	require.EqualError(t, err, "failed to query Prometheus label values: bad_data: unhandled match")
+	require.False(t, promapi.IsUnavailableError(err))
+}
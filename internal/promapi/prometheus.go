@@ -1,6 +1,8 @@
 package promapi
 
 import (
+	"crypto/tls"
+	"net/http"
 	"sync"
 	"time"
 
@@ -12,32 +14,101 @@ import (
 type Prometheus struct {
 	name    string
 	uri     string
+	client  api.Client
 	api     v1.API
 	timeout time.Duration
 	cache   *lru.Cache
+	disk    *DiskCache
+	session bool
 	lock    *partitionLocker
-
-	slowQueryCache *lru.Cache
-	slowQueryLock  sync.Mutex
 }
 
-func NewPrometheus(name, uri string, timeout time.Duration) *Prometheus {
-	client, err := api.NewClient(api.Config{Address: uri})
-	if err != nil {
-		// config validation should prevent this from ever happening
-		// panic so we don't need to return an error and it's easier to
-		// use this code in tests
-		panic(err)
+// NewPrometheus creates a new Prometheus API client. All requests will include
+// given headers and will use tlsConf for https connections if it's not nil.
+func NewPrometheus(name, uri string, headers HeadersFunc, timeout time.Duration, tlsConf *tls.Config) *Prometheus {
+	var client api.Client
+	if path, ok := FixturePath(uri); ok {
+		client = newFixtureClient(path, timeout)
+	} else {
+		var err error
+		client, err = api.NewClient(api.Config{
+			Address:      uri,
+			RoundTripper: newRoundTripper(headers, tlsConf),
+		})
+		if err != nil {
+			// config validation should prevent this from ever happening
+			// panic so we don't need to return an error and it's easier to
+			// use this code in tests
+			panic(err)
+		}
 	}
 	cache, _ := lru.New(1000)
-	slowQueryCache, _ := lru.New(1000)
 	return &Prometheus{
-		name:           name,
-		uri:            uri,
-		api:            v1.NewAPI(client),
-		timeout:        timeout,
-		cache:          cache,
-		lock:           newPartitionLocker((&sync.Mutex{})),
-		slowQueryCache: slowQueryCache,
+		name:    name,
+		uri:     uri,
+		client:  client,
+		api:     v1.NewAPI(client),
+		timeout: timeout,
+		cache:   cache,
+		lock:    newPartitionLocker((&sync.Mutex{})),
+	}
+}
+
+// SetDiskCache enables storing query results on disk, in addition
+// to the in-memory cache.
+// It's ignored for fixture files, so any edits to them are visible
+// immediately, and for servers using a session, since every response
+// must be recorded or replayed by that session.
+func (p *Prometheus) SetDiskCache(dc *DiskCache) {
+	if _, ok := FixturePath(p.uri); ok || p.session {
+		return
+	}
+	p.disk = dc
+}
+
+// SetSession will record all responses from this server or replay them
+// from given session, depending on the session mode.
+// It disables the disk cache.
+func (p *Prometheus) SetSession(s *Session) {
+	p.api = v1.NewAPI(s.client(p.uri, p.client))
+	p.session = true
+	p.disk = nil
+	p.cache.Purge()
+}
+
+func newRoundTripper(headers HeadersFunc, tlsConf *tls.Config) http.RoundTripper {
+	rt := api.DefaultRoundTripper
+	if tlsConf != nil {
+		transport := rt.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConf
+		rt = transport
+	}
+	if headers != nil {
+		rt = headersRoundTripper{headers: headers, rt: rt}
+	}
+	return rt
+}
+
+// HeadersFunc returns extra HTTP headers to set on requests.
+// It's called for every request, so credentials read from files or
+// environment variables can be rotated while pint is running.
+type HeadersFunc func() (map[string]string, error)
+
+// headersRoundTripper sets extra HTTP headers on every request.
+type headersRoundTripper struct {
+	headers HeadersFunc
+	rt      http.RoundTripper
+}
+
+func (hrt headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers, err := hrt.headers()
+	if err != nil {
+		return nil, err
+	}
+	// RoundTripper must not modify the original request
+	req = req.Clone(req.Context())
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
+	return hrt.rt.RoundTrip(req)
 }
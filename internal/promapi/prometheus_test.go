@@ -0,0 +1,80 @@
+package promapi_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestPrometheusHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Scope-OrgID") != "tenant" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(401)
+			_, _ = w.Write([]byte("Unauthorized"))
+			return
+		}
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	_, err := prom.Query(context.Background(), "up")
+	require.EqualError(t, err, "client_error: client error: 401")
+
+	token := "secret"
+	prom = promapi.NewPrometheus("prom", srv.URL, func() (map[string]string, error) {
+		if token == "" {
+			return nil, errors.New("token is empty")
+		}
+		return map[string]string{
+			"X-Scope-OrgID": "tenant",
+			"Authorization": "Bearer " + token,
+		}, nil
+	}, time.Second, nil)
+	qr, err := prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Empty(t, qr.Series)
+
+	// headers are resolved on every request
+	token = "rotated"
+	_, err = prom.Query(context.Background(), "foo")
+	require.EqualError(t, err, "client_error: client error: 401")
+
+	token = ""
+	_, err = prom.Query(context.Background(), "bar")
+	require.ErrorContains(t, err, "token is empty")
+}
+
+func TestPrometheusTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	_, err := prom.Query(context.Background(), "up")
+	require.ErrorContains(t, err, "certificate")
+
+	prom = promapi.NewPrometheus("prom", srv.URL, nil, time.Second, &tls.Config{InsecureSkipVerify: true})
+	_, err = prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	prom = promapi.NewPrometheus("prom", srv.URL, nil, time.Second, &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})
+	_, err = prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+}
@@ -31,6 +31,13 @@ func (p *Prometheus) Query(ctx context.Context, expr string) (*QueryResult, erro
 		return &r, nil
 	}
 
+	var cached QueryResult
+	if p.disk.get(p.uri, "/api/v1/query", expr, &cached) {
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/query").Inc()
+		p.cache.Add(expr, cached)
+		return &cached, nil
+	}
+
 	log.Debug().Str("uri", p.uri).Str("query", expr).Msg("Query started")
 
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
@@ -72,6 +79,7 @@ func (p *Prometheus) Query(ctx context.Context, expr string) (*QueryResult, erro
 
 	log.Debug().Str("key", expr).Str("uri", p.uri).Msg("Query cache miss")
 	p.cache.Add(expr, qr)
+	p.disk.set(p.uri, "/api/v1/query", expr, qr)
 
 	return &qr, nil
 }
@@ -212,7 +212,7 @@ func TestQuery(t *testing.T) {
 		t.Run(tc.query, func(t *testing.T) {
 			assert := assert.New(t)
 
-			prom := promapi.NewPrometheus("test", srv.URL, tc.timeout)
+			prom := promapi.NewPrometheus("test", srv.URL, nil, tc.timeout, nil)
 
 			wg := sync.WaitGroup{}
 			wg.Add(tc.runs)
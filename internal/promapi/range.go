@@ -2,9 +2,10 @@ package promapi
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -14,12 +15,34 @@ import (
 	"github.com/cloudflare/pint/internal/output"
 )
 
+const (
+	// Long range queries are split into chunks of this size.
+	rangeQueryChunkSize = time.Hour * 24
+	// Chunks that fail with an error that might be caused by the size of the
+	// queried time range will be split in half at most this many times.
+	rangeQueryMaxSplits = 2
+	// Maximum number of chunks queried at the same time for a single query.
+	rangeQueryConcurrency = 4
+)
+
 type RangeQueryResult struct {
 	URI             string
 	Samples         []*model.SampleStream
 	Start           time.Time
 	End             time.Time
 	DurationSeconds float64
+	// Failed is a list of time ranges for which queries failed,
+	// there are no samples for these ranges in the result.
+	Failed []v1.Range
+}
+
+// Missing returns the total duration of all time ranges for which
+// queries failed.
+func (qr RangeQueryResult) Missing() (d time.Duration) {
+	for _, r := range joinRanges(qr.Failed) {
+		d += r.End.Sub(r.Start)
+	}
+	return d
 }
 
 func (p *Prometheus) RangeQuery(ctx context.Context, expr string, lookback, step time.Duration) (*RangeQueryResult, error) {
@@ -35,14 +58,6 @@ func (p *Prometheus) RangeQuery(ctx context.Context, expr string, lookback, step
 	defer p.lock.unlock(lockKey)
 
 	cacheKey := strings.Join([]string{expr, lookback.String(), step.String()}, "\n")
-	return p.realRangeQuery(ctx, expr, lookback, step, cacheKey, false)
-}
-
-func (p *Prometheus) realRangeQuery(
-	ctx context.Context,
-	expr string, lookback, step time.Duration,
-	cacheKey string, isRetry bool,
-) (*RangeQueryResult, error) {
 	if v, ok := p.cache.Get(cacheKey); ok {
 		log.Debug().
 			Str("uri", p.uri).
@@ -54,6 +69,13 @@ func (p *Prometheus) realRangeQuery(
 		r := v.(RangeQueryResult)
 		return &r, nil
 	}
+
+	var cached RangeQueryResult
+	if p.disk.get(p.uri, "/api/v1/query_range", cacheKey, &cached) {
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/query_range").Inc()
+		p.cache.Add(cacheKey, cached)
+		return &cached, nil
+	}
 	log.Debug().
 		Str("uri", p.uri).
 		Str("query", expr).
@@ -61,39 +83,23 @@ func (p *Prometheus) realRangeQuery(
 		Str("step", output.HumanizeDuration(step)).
 		Msg("Cache miss")
 
-	prometheusQueriesTotal.WithLabelValues(p.name, "/api/v1/query_range").Inc()
 	now := time.Now()
 	r := v1.Range{
 		Start: now.Add(lookback * -1),
 		End:   now,
 		Step:  step,
 	}
-
-	if !isRetry {
-		p.slowQueryLock.Lock()
-		if v, ok := p.slowQueryCache.Get(expr); ok {
-			log.Debug().
-				Str("query", expr).
-				Str("delta", output.HumanizeDuration(v.(time.Duration))).
-				Str("start", r.Start.String()).
-				Str("cached", r.End.Add(v.(time.Duration)*-1).String()).
-				Msg("Got cached slow query delta")
-			r.Start = r.End.Add(v.(time.Duration) * -1)
-		}
-		p.slowQueryLock.Unlock()
-	}
-
-	rctx, cancel := context.WithTimeout(ctx, p.timeout)
-	defer cancel()
+	chunks := splitRange(r, rangeQueryChunkSize)
 
 	log.Debug().
 		Str("uri", p.uri).
 		Str("query", expr).
 		Str("delta", output.HumanizeDuration(lookback)).
-		Bool("retry", isRetry).
+		Int("chunks", len(chunks)).
 		Msg("Executing range query")
+
 	qstart := time.Now()
-	result, _, err := p.api.QueryRange(rctx, expr, r)
+	samples, failed, succeeded, err := p.queryChunks(ctx, expr, chunks)
 	duration := time.Since(qstart)
 	log.Debug().
 		Str("uri", p.uri).
@@ -101,43 +107,265 @@ func (p *Prometheus) realRangeQuery(
 		Str("duration", output.HumanizeDuration(duration)).
 		Msg("Range query completed")
 	if err != nil {
-		log.Error().Err(err).Str("uri", p.uri).Str("query", expr).Msg("Range query failed")
-		prometheusQueryErrorsTotal.WithLabelValues(p.name, "/api/v1/query_range", errReason(err)).Inc()
-		if delta, retryOK := CanRetryError(err, lookback); retryOK {
-			if delta < step*2 {
-				log.Error().Str("uri", p.uri).Str("query", expr).Msg("No more retries possible")
-				return nil, errors.New("no more retries possible")
-			}
-			log.Warn().Str("delta", output.HumanizeDuration(delta)).Msg("Retrying request with smaller range")
-			p.slowQueryLock.Lock()
-			p.slowQueryCache.Remove(expr)
-			p.slowQueryCache.Add(expr, delta)
-			p.slowQueryLock.Unlock()
-			return p.realRangeQuery(ctx, expr, delta, step, cacheKey, true)
-		}
 		return nil, err
 	}
 
 	qr := RangeQueryResult{
 		URI:             p.uri,
-		DurationSeconds: duration.Seconds(),
-		Start:           r.Start,
+		Samples:         samples,
+		Start:           chunks[0].r.Start,
 		End:             r.End,
+		DurationSeconds: duration.Seconds(),
+		Failed:          failed,
+	}
+	if len(failed) > 0 {
+		if succeeded == 0 {
+			log.Error().Str("uri", p.uri).Str("query", expr).Msg("Range query failed for the whole time range")
+			return nil, fmt.Errorf("range query failed for all time ranges: %s", formatRanges(failed))
+		}
+		log.Warn().
+			Str("uri", p.uri).
+			Str("query", expr).
+			Str("missing", output.HumanizeDuration(qr.Missing())).
+			Msg("Range query failed for some time ranges, results are incomplete")
+	}
+	log.Debug().Str("uri", p.uri).Str("query", expr).Int("samples", len(qr.Samples)).Msg("Parsed range response")
+
+	log.Debug().Str("query", expr).Str("uri", p.uri).Msg("Range query cache miss")
+	p.cache.Add(cacheKey, qr)
+	if len(failed) == 0 {
+		p.disk.set(p.uri, "/api/v1/query_range", cacheKey, qr)
+	}
+
+	return &qr, nil
+}
+
+// queryChunks runs queries for all chunks, with bounded concurrency, and merges
+// the results. It also returns time ranges that couldn't be queried and the
+// number of queries that succeeded. It will return an error if any chunk fails
+// with an error that can't be fixed by querying a smaller time range.
+func (p *Prometheus) queryChunks(ctx context.Context, expr string, chunks []rangeChunk) ([]*model.SampleStream, []v1.Range, int, error) {
+	type chunkResult struct {
+		parts  [][]*model.SampleStream
+		failed []v1.Range
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chunkResult, len(chunks))
+	sem := make(chan struct{}, rangeQueryConcurrency)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for i, c := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, c rangeChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			parts, failed, err := p.queryChunk(cctx, expr, c, 0)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = chunkResult{parts: parts, failed: failed}
+		}(i, c)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, 0, firstErr
+	}
+
+	// every successful query returns one part, even if it has no samples
+	var parts [][]*model.SampleStream
+	var failed []v1.Range
+	for _, cr := range results {
+		parts = append(parts, cr.parts...)
+		failed = append(failed, cr.failed...)
+	}
+	return mergeSamples(parts), failed, len(parts), nil
+}
+
+// queryChunk returns results of all queries needed to get samples for given
+// chunk, in chronological order, and time ranges for which all queries failed.
+func (p *Prometheus) queryChunk(ctx context.Context, expr string, c rangeChunk, splits int) ([][]*model.SampleStream, []v1.Range, error) {
+	prometheusQueriesTotal.WithLabelValues(p.name, "/api/v1/query_range").Inc()
+
+	rctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	log.Debug().
+		Str("uri", p.uri).
+		Str("query", expr).
+		Str("start", c.r.Start.String()).
+		Str("end", c.r.End.String()).
+		Int("splits", splits).
+		Msg("Executing range query chunk")
+	result, _, err := p.api.QueryRange(rctx, expr, c.r)
+	if err != nil {
+		log.Error().Err(err).Str("uri", p.uri).Str("query", expr).Msg("Range query failed")
+		prometheusQueryErrorsTotal.WithLabelValues(p.name, "/api/v1/query_range", errReason(err)).Inc()
+		if _, retryOK := CanRetryError(err, c.r.End.Sub(c.r.Start)); !retryOK || ctx.Err() != nil {
+			return nil, nil, err
+		}
+		older, newer, ok := c.split()
+		if !ok || splits >= rangeQueryMaxSplits {
+			log.Warn().
+				Str("uri", p.uri).
+				Str("query", expr).
+				Str("start", c.r.Start.String()).
+				Str("end", c.r.End.String()).
+				Msg("No more retries possible for range query chunk")
+			return nil, []v1.Range{c.r}, nil
+		}
+		log.Warn().Str("uri", p.uri).Str("query", expr).Msg("Retrying range query chunk with smaller range")
+		var parts [][]*model.SampleStream
+		var failed []v1.Range
+		for _, sc := range []rangeChunk{older, newer} {
+			sp, sf, err := p.queryChunk(ctx, expr, sc, splits+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			parts = append(parts, sp...)
+			failed = append(failed, sf...)
+		}
+		return parts, failed, nil
 	}
 
 	switch result.Type() {
 	case model.ValMatrix:
-		samples := result.(model.Matrix)
-		qr.Samples = samples
+		return [][]*model.SampleStream{c.filter(result.(model.Matrix))}, nil, nil
 	default:
 		log.Error().Err(err).Str("uri", p.uri).Str("query", expr).Msgf("Range query returned unknown result type: %v", result.Type())
 		prometheusQueryErrorsTotal.WithLabelValues(p.name, "/api/v1/query_range", "unknown result type").Inc()
-		return nil, fmt.Errorf("unknown result type: %v", result.Type())
+		return nil, nil, fmt.Errorf("unknown result type: %v", result.Type())
 	}
-	log.Debug().Str("uri", p.uri).Str("query", expr).Int("samples", len(qr.Samples)).Msg("Parsed range response")
+}
 
-	log.Debug().Str("query", expr).Str("uri", p.uri).Msg("Range query cache miss")
-	p.cache.Add(cacheKey, qr)
+// rangeChunk is a part of the time range of a range query.
+// Chunks don't overlap and together cover the whole time range.
+type rangeChunk struct {
+	r      v1.Range
+	oldest bool
+	newest bool
+}
 
-	return &qr, nil
+// splitRange splits the time range into chunks of given size, chunks are
+// aligned with the step of the query, counting back from the end of it.
+// The start of the range is moved forward to the first timestamp on that
+// step grid, so chunks will return samples with the same timestamps as
+// a single query for the whole range would.
+func splitRange(r v1.Range, size time.Duration) (chunks []rangeChunk) {
+	size = size.Truncate(r.Step)
+	if size < r.Step {
+		size = r.Step
+	}
+
+	start := r.Start.Add(r.End.Sub(r.Start) % r.Step)
+	end := r.End
+	for end.Add(size * -1).After(start) {
+		chunks = append([]rangeChunk{{r: v1.Range{Start: end.Add(r.Step - size), End: end, Step: r.Step}}}, chunks...)
+		end = end.Add(size * -1)
+	}
+	chunks = append([]rangeChunk{{r: v1.Range{Start: start, End: end, Step: r.Step}}}, chunks...)
+
+	chunks[0].oldest = true
+	chunks[len(chunks)-1].newest = true
+	return chunks
+}
+
+// split returns two chunks, each covering half of this chunk.
+func (c rangeChunk) split() (older, newer rangeChunk, ok bool) {
+	steps := int64(c.r.End.Sub(c.r.Start) / c.r.Step)
+	if steps < 2 {
+		return older, newer, false
+	}
+	mid := c.r.End.Add(c.r.Step * time.Duration(steps/2) * -1)
+	older = rangeChunk{r: v1.Range{Start: c.r.Start, End: mid, Step: c.r.Step}, oldest: c.oldest}
+	newer = rangeChunk{r: v1.Range{Start: mid.Add(c.r.Step), End: c.r.End, Step: c.r.Step}, newest: c.newest}
+	return older, newer, true
+}
+
+// filter removes all samples belonging to other chunks.
+// Returned timestamps have millisecond precision, so chunk boundaries are
+// moved by half of the step to be sure that no sample is lost.
+func (c rangeChunk) filter(matrix model.Matrix) (samples []*model.SampleStream) {
+	from := c.r.Start.Add(c.r.Step / -2)
+	until := c.r.End.Add(c.r.Step / 2)
+	for _, ss := range matrix {
+		values := make([]model.SamplePair, 0, len(ss.Values))
+		for _, v := range ss.Values {
+			ts := v.Timestamp.Time()
+			if !c.oldest && ts.Before(from) {
+				continue
+			}
+			if !c.newest && !ts.Before(until) {
+				continue
+			}
+			values = append(values, v)
+		}
+		if len(values) > 0 {
+			samples = append(samples, &model.SampleStream{Metric: ss.Metric, Values: values})
+		}
+	}
+	return samples
+}
+
+// mergeSamples merges results of all queries, samples for the same series
+// are joined together and sorted by timestamp.
+func mergeSamples(parts [][]*model.SampleStream) []*model.SampleStream {
+	merged := []*model.SampleStream{}
+	index := map[model.Fingerprint]*model.SampleStream{}
+	for _, part := range parts {
+		for _, ss := range part {
+			fp := ss.Metric.Fingerprint()
+			m, ok := index[fp]
+			if !ok {
+				m = &model.SampleStream{Metric: ss.Metric}
+				index[fp] = m
+				merged = append(merged, m)
+			}
+			m.Values = append(m.Values, ss.Values...)
+		}
+	}
+	for _, m := range merged {
+		if !sort.SliceIsSorted(m.Values, func(i, j int) bool { return m.Values[i].Timestamp < m.Values[j].Timestamp }) {
+			sort.SliceStable(m.Values, func(i, j int) bool { return m.Values[i].Timestamp < m.Values[j].Timestamp })
+		}
+	}
+	return merged
+}
+
+// joinRanges returns a sorted copy of given time ranges with adjacent
+// ranges joined together.
+func joinRanges(ranges []v1.Range) (joined []v1.Range) {
+	sorted := make([]v1.Range, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	for _, r := range sorted {
+		if n := len(joined); n > 0 && !r.Start.After(joined[n-1].End.Add(r.Step)) {
+			if r.End.After(joined[n-1].End) {
+				joined[n-1].End = r.End
+			}
+			continue
+		}
+		joined = append(joined, r)
+	}
+	return joined
+}
+
+// formatRanges returns a human readable list of given time ranges.
+func formatRanges(ranges []v1.Range) string {
+	joined := joinRanges(ranges)
+	parts := make([]string, 0, len(joined))
+	for _, r := range joined {
+		parts = append(parts, fmt.Sprintf("%s - %s", r.Start.UTC().Format(time.RFC3339), r.End.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(parts, ", ")
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -27,25 +28,27 @@ func TestRange(t *testing.T) {
 		start, _ := strconv.ParseFloat(r.Form.Get("start"), 64)
 		end, _ := strconv.ParseFloat(r.Form.Get("end"), 64)
 		diff := time.Unix(int64(end), 0).Sub(time.Unix(int64(start), 0))
+		age := time.Since(time.Unix(int64(end), 0))
 		t.Logf("query=%s diff=%s start=%s end=%s", query, diff, time.Unix(int64(start), 0), time.Unix(int64(end), 0))
 
-		if diffs, ok := done.Load(query); ok {
-			doneDiffs := diffs.([]string)
-			for _, doneDiff := range doneDiffs {
+		key := r.Form.Get("start") + "/" + r.Form.Get("end")
+		if keys, ok := done.Load(query); ok {
+			doneKeys := keys.([]string)
+			for _, doneKey := range doneKeys {
 				// some queries are allowed to re-run because they fail and never cache anything
-				if doneDiff == diff.String() &&
+				if doneKey == key &&
 					query != "too_many_samples1" &&
 					query != "error1" && query != "error2" &&
 					query != "vector1" && query != "vector2" &&
 					query != "slow1" && query != "timeout1" && query != "gateway_timeout1" {
-					t.Errorf("%q already requested diff=%s", query, diff)
+					t.Errorf("%q already requested start/end=%s", query, key)
 					t.FailNow()
 				}
 			}
-			doneDiffs = append(doneDiffs, diff.String())
-			done.Store(query, doneDiffs)
+			doneKeys = append(doneKeys, key)
+			done.Store(query, doneKeys)
 		} else {
-			done.Store(query, []string{diff.String()})
+			done.Store(query, []string{key})
 		}
 
 		switch query {
@@ -81,35 +84,18 @@ func TestRange(t *testing.T) {
 			w.Header().Set("Content-Type", "application/json")
 			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
 		case "timeout1":
-			switch diff.String() {
-			case "168h0m0s", "42h0m0s", "10h30m0s", "2h38m0s", "40m0s", "10m0s":
-				w.WriteHeader(503)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`{
-					"status": "error",
-					"errorType": "timeout",
-					"error": "query timed out in expression evaluation"
-				}`))
-			default:
-				t.Errorf("invalid timeout diff: %s", diff)
-				w.WriteHeader(500)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`unknown start/end`))
-			}
+			w.WriteHeader(503)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"status": "error",
+				"errorType": "timeout",
+				"error": "query timed out in expression evaluation"
+			}`))
 		case "gateway_timeout1":
-			switch diff.String() {
-			case "168h0m0s", "42h0m0s", "10h30m0s", "2h38m0s", "40m0s", "10m0s":
-				w.WriteHeader(504)
-				_, _ = w.Write([]byte(`504 Gateway Time-out`))
-			default:
-				t.Errorf("invalid timeout diff: %s", diff)
-				w.WriteHeader(500)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`unknown start/end`))
-			}
+			w.WriteHeader(504)
+			_, _ = w.Write([]byte(`504 Gateway Time-out`))
 		case "timeout_until_success1":
-			switch diff.String() {
-			case "168h0m0s", "42h0m0s", "10h30m0s", "2h38m0s":
+			if diff > time.Hour*7 {
 				w.WriteHeader(503)
 				w.Header().Set("Content-Type", "application/json")
 				_, _ = w.Write([]byte(`{
@@ -117,39 +103,25 @@ func TestRange(t *testing.T) {
 					"errorType": "timeout",
 					"error": "query timed out in expression evaluation"
 				}`))
-			case "40m0s":
-				w.WriteHeader(200)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
-								{"metric":{"instance":"1"},"values":[
-									[1614859502.068,"0"]
-								]}
-							]}}`))
-			default:
-				t.Errorf("invalid timeout diff: %s", diff)
-				w.WriteHeader(500)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`unknown start/end`))
+				return
 			}
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"instance":"1"},"values":[
+					[1614859502.068,"0"]
+				]}
+			]}}`))
 		case "too_many_samples1":
-			switch diff.String() {
-			case "168h0m0s", "42h0m0s", "10h30m0s", "2h38m0s", "40m0s", "10m0s":
-				w.WriteHeader(422)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`{
-					"status":"error",
-					"errorType":"execution",
-					"error":"query processing would load too many samples into memory in query execution"
-				}`))
-			default:
-				t.Errorf("invalid too_many_samples diff: %s", diff)
-				w.WriteHeader(500)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`unknown start/end`))
-			}
+			w.WriteHeader(422)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"status":"error",
+				"errorType":"execution",
+				"error":"query processing would load too many samples into memory in query execution"
+			}`))
 		case "duplicate_series1":
-			switch diff.String() {
-			case "168h0m0s", "84h0m0s", "42h0m0s", "21h0m0s", "10h30m0s":
+			if diff > time.Hour*7 {
 				w.WriteHeader(422)
 				w.Header().Set("Content-Type", "application/json")
 				_, _ = w.Write([]byte(`{
@@ -157,23 +129,17 @@ func TestRange(t *testing.T) {
 					"errorType":"execution",
 					"error":"found duplicate series for the match group {...} on the right hand-side of the operation: [{...}, {...}];many-to-many matching not allowed: matching labels must be unique on one side"
 				}`))
-			case "5h15m0s":
-				w.WriteHeader(200)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
-							{"metric":{"instance":"1"},"values":[
-								[1614859502.068,"0"]
-							]}
-						]}}`))
-			default:
-				t.Errorf("invalid duplicate_series diff: %s", diff)
-				w.WriteHeader(500)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`unknown start/end`))
+				return
 			}
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"instance":"1"},"values":[
+					[1614859502.068,"0"]
+				]}
+			]}}`))
 		case "retry_until_success1", "retry_until_success2":
-			switch diff.String() {
-			case "168h0m0s", "42h0m0s", "10h30m0s", "2h38m0s":
+			if diff > time.Hour*7 {
 				w.WriteHeader(422)
 				w.Header().Set("Content-Type", "application/json")
 				_, _ = w.Write([]byte(`{
@@ -181,24 +147,78 @@ func TestRange(t *testing.T) {
 					"errorType":"execution",
 					"error":"query processing would load too many samples into memory in query execution"
 				}`))
-			case "40m0s":
-				w.WriteHeader(200)
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
-						{"metric":{"instance":"1"},"values":[
-							[1614859502.068,"0"],
-							[1614859562.068,"1"],
-							[1614859622.068,"3"],
-							[1614859682.068,"4"],
-							[1614859742.068,"11"]
-						]}
-					]}}`))
-			default:
-				t.Errorf("invalid retry_until_success diff: %s", diff)
-				w.WriteHeader(500)
+				return
+			}
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"instance":"1"},"values":[
+					[1614859502.068,"0"],
+					[1614859562.068,"1"],
+					[1614859622.068,"3"],
+					[1614859682.068,"4"],
+					[1614859742.068,"11"]
+				]}
+			]}}`))
+		case "partial1":
+			// only the last 3 days can be queried
+			if age > time.Hour*71 {
+				w.WriteHeader(503)
 				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`unknown start/end`))
+				_, _ = w.Write([]byte(`{
+					"status": "error",
+					"errorType": "timeout",
+					"error": "query timed out in expression evaluation"
+				}`))
+				return
 			}
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"instance":"1"},"values":[
+					[1614859502.068,"1"]
+				]}
+			]}}`))
+		case "chunks1", "chunks2":
+			// return a sample for every step in the requested range
+			step, _ := strconv.ParseFloat(r.Form.Get("step"), 64)
+			values := ""
+			for ts := start; ts <= end; ts += step {
+				if values != "" {
+					values += ","
+				}
+				values += `[` + strconv.FormatFloat(ts, 'f', 3, 64) + `,"1"]`
+			}
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"instance":"1"},"values":[` + values + `]}
+			]}}`))
+		case "duplicates1":
+			// return a sample for every step in the requested range, split
+			// between two series with identical labels
+			step, _ := strconv.ParseFloat(r.Form.Get("step"), 64)
+			var even, odd string
+			for i, ts := 0, start; ts <= end; i, ts = i+1, ts+step {
+				v := `[` + strconv.FormatFloat(ts, 'f', 3, 64) + `,"1"]`
+				if i%2 == 0 {
+					if even != "" {
+						even += ","
+					}
+					even += v
+				} else {
+					if odd != "" {
+						odd += ","
+					}
+					odd += v
+				}
+			}
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"instance":"1"},"values":[` + even + `]},
+				{"metric":{"instance":"1"},"values":[` + odd + `]}
+			]}}`))
 		default:
 			w.WriteHeader(400)
 			w.Header().Set("Content-Type", "application/json")
@@ -213,7 +233,10 @@ func TestRange(t *testing.T) {
 		step     time.Duration
 		timeout  time.Duration
 		samples  []*model.SampleStream
+		points   int
+		missing  time.Duration
 		err      string
+		errStart string
 		runs     int
 	}
 
@@ -318,7 +341,7 @@ func TestRange(t *testing.T) {
 			lookback: time.Hour * 24 * 7,
 			step:     time.Minute * 5,
 			timeout:  time.Second,
-			err:      "no more retries possible",
+			errStart: "range query failed for all time ranges: ",
 			runs:     5,
 		},
 		// retry timeouts
@@ -327,7 +350,7 @@ func TestRange(t *testing.T) {
 			lookback: time.Hour * 24 * 7,
 			step:     time.Minute * 5,
 			timeout:  time.Millisecond * 20,
-			err:      "no more retries possible",
+			errStart: "range query failed for all time ranges: ",
 			runs:     5,
 		},
 		// retry query timeouts
@@ -336,14 +359,14 @@ func TestRange(t *testing.T) {
 			lookback: time.Hour * 24 * 7,
 			step:     time.Minute * 5,
 			timeout:  time.Millisecond * 20,
-			err:      "no more retries possible",
+			errStart: "range query failed for all time ranges: ",
 			runs:     5,
 		},
 		{
 			query:    "timeout_until_success1",
 			lookback: time.Hour * 24 * 7,
 			step:     time.Minute * 5,
-			timeout:  time.Millisecond * 20,
+			timeout:  time.Second,
 			samples: []*model.SampleStream{
 				{
 					Metric: model.Metric{"instance": "1"},
@@ -359,7 +382,7 @@ func TestRange(t *testing.T) {
 			lookback: time.Hour * 24 * 7,
 			step:     time.Minute * 5,
 			timeout:  time.Second,
-			err:      "no more retries possible",
+			errStart: "range query failed for all time ranges: ",
 			runs:     5,
 		},
 		// cache hit
@@ -418,26 +441,77 @@ func TestRange(t *testing.T) {
 			},
 			runs: 5,
 		},
+		// some chunks failed
+		{
+			query:    "partial1",
+			lookback: time.Hour * 24 * 7,
+			step:     time.Minute * 5,
+			timeout:  time.Second,
+			samples:  []*model.SampleStream{},
+			missing:  time.Hour * 96,
+			runs:     5,
+		},
+		// samples from all chunks are merged
+		{
+			query:    "chunks1",
+			lookback: time.Hour * 24 * 7,
+			step:     time.Minute * 5,
+			timeout:  time.Second,
+			points:   int(time.Hour*24*7/(time.Minute*5)) + 1,
+			runs:     5,
+		},
+		// lookback isn't a multiple of step
+		{
+			query:    "chunks2",
+			lookback: time.Hour*24*7 + time.Minute*2 + time.Second*30,
+			step:     time.Minute * 5,
+			timeout:  time.Second,
+			points:   int(time.Hour*24*7/(time.Minute*5)) + 1,
+			runs:     5,
+		},
+		// series with identical labels within and across chunks are merged
+		{
+			query:    "duplicates1",
+			lookback: time.Hour * 24 * 7,
+			step:     time.Minute * 5,
+			timeout:  time.Second,
+			points:   int(time.Hour*24*7/(time.Minute*5)) + 1,
+			runs:     5,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.query, func(t *testing.T) {
 			assert := assert.New(t)
 
-			prom := promapi.NewPrometheus("test", srv.URL, tc.timeout)
+			prom := promapi.NewPrometheus("test", srv.URL, nil, tc.timeout, nil)
 
 			wg := sync.WaitGroup{}
 			wg.Add(tc.runs)
 			for i := 1; i <= tc.runs; i++ {
 				go func() {
 					qr, err := prom.RangeQuery(context.Background(), tc.query, tc.lookback, tc.step)
-					if tc.err != "" {
+					switch {
+					case tc.err != "":
 						assert.EqualError(err, tc.err, tc)
-					} else {
+					case tc.errStart != "":
+						if assert.Error(err, tc) {
+							assert.True(strings.HasPrefix(err.Error(), tc.errStart), err.Error())
+						}
+					default:
 						assert.NoError(err)
 					}
 					if qr != nil {
-						assert.Equal(qr.Samples, tc.samples, tc)
+						assert.Equal(tc.missing, qr.Missing(), tc)
+						if tc.points > 0 {
+							assert.Len(qr.Samples, 1, tc)
+							assert.Len(qr.Samples[0].Values, tc.points, tc)
+							for i := 1; i < len(qr.Samples[0].Values); i++ {
+								assert.Equal(tc.step, qr.Samples[0].Values[i].Timestamp.Sub(qr.Samples[0].Values[i-1].Timestamp), tc)
+							}
+						} else {
+							assert.Equal(qr.Samples, tc.samples, tc)
+						}
 					}
 					wg.Done()
 				}()
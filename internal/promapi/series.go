@@ -0,0 +1,67 @@
+package promapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cloudflare/pint/internal/output"
+)
+
+type SeriesResult struct {
+	URI    string
+	Series []model.LabelSet
+	Start  time.Time
+	End    time.Time
+}
+
+func (p *Prometheus) Series(ctx context.Context, matches []string, lookback time.Duration) (*SeriesResult, error) {
+	log.Debug().
+		Str("uri", p.uri).
+		Strs("matches", matches).
+		Str("lookback", output.HumanizeDuration(lookback)).
+		Msg("Query Prometheus series")
+
+	key := strings.Join(append([]string{"/api/v1/series", lookback.String()}, matches...), "\n")
+	p.lock.lock(key)
+	defer p.lock.unlock(key)
+
+	if v, ok := p.cache.Get(key); ok {
+		log.Debug().Str("key", key).Str("uri", p.uri).Msg("Series cache hit")
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/series").Inc()
+		r := v.(SeriesResult)
+		return &r, nil
+	}
+
+	var cached SeriesResult
+	if p.disk.get(p.uri, "/api/v1/series", key, &cached) {
+		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/series").Inc()
+		p.cache.Add(key, cached)
+		return &cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	prometheusQueriesTotal.WithLabelValues(p.name, "/api/v1/series").Inc()
+	end := time.Now()
+	start := end.Add(lookback * -1)
+	series, _, err := p.api.Series(ctx, matches, start, end)
+	if err != nil {
+		log.Error().Err(err).Str("uri", p.uri).Strs("matches", matches).Msg("Failed to query Prometheus series")
+		prometheusQueryErrorsTotal.WithLabelValues(p.name, "/api/v1/series", errReason(err)).Inc()
+		return nil, fmt.Errorf("failed to query Prometheus series: %w", err)
+	}
+
+	r := SeriesResult{URI: p.uri, Series: series, Start: start, End: end}
+
+	log.Debug().Str("key", key).Str("uri", p.uri).Int("series", len(series)).Msg("Series cache miss")
+	p.cache.Add(key, r)
+	p.disk.set(p.uri, "/api/v1/series", key, r)
+
+	return &r, nil
+}
@@ -0,0 +1,71 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestSeries(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		start, _ := strconv.ParseFloat(r.URL.Query().Get("start"), 64)
+		end, _ := strconv.ParseFloat(r.URL.Query().Get("end"), 64)
+		if diff := time.Duration(end-start) * time.Second; diff != time.Hour {
+			t.Errorf("bad time range: %s", diff)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("match[]") {
+		case "up":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`))
+		case "missing":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+		case "error":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		default:
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled match"}`))
+		}
+	}))
+	defer srv.Close()
+
+	prom := promapi.NewPrometheus("test", srv.URL, nil, time.Second, nil)
+
+	for i := 0; i < 5; i++ {
+		sr, err := prom.Series(context.Background(), []string{"up"}, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, srv.URL, sr.URI)
+		require.Equal(t, []model.LabelSet{
+			{"__name__": "up", "job": "a"},
+			{"__name__": "up", "job": "b"},
+		}, sr.Series)
+		require.Equal(t, time.Hour, sr.End.Sub(sr.Start))
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	sr, err := prom.Series(context.Background(), []string{"missing"}, time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, sr.Series)
+
+	_, err = prom.Series(context.Background(), []string{"error"}, time.Hour)
+	require.EqualError(t, err, "failed to query Prometheus series: server_error: server error: 500")
+	require.True(t, promapi.IsUnavailableError(err))
+
+	_, err = prom.Series(context.Background(), []string{"foo"}, time.Hour)
+	require.EqualError(t, err, "failed to query Prometheus series: bad_data: unhandled match")
+	require.False(t, promapi.IsUnavailableError(err))
+}
@@ -0,0 +1,342 @@
+package promapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	"github.com/prometheus/common/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Session allows to record all Prometheus API responses to a file and later
+// replay them without sending any requests to Prometheus servers.
+type Session struct {
+	path    string
+	replay  bool
+	lock    sync.Mutex
+	file    *os.File
+	entries map[string][]sessionEntry
+}
+
+// sessionEntry is a single recorded API response, stored as one JSON
+// document per line in the session file.
+type sessionEntry struct {
+	URI    string `json:"uri"`
+	Path   string `json:"path"`
+	Params string `json:"params,omitempty"`
+	Time   string `json:"time,omitempty"`
+	// Offset is the number of seconds between the query time and the moment
+	// it was sent, it allows to tell apart queries for different chunks of
+	// the same range query.
+	Offset float64 `json:"offset,omitempty"`
+	Code   int     `json:"code,omitempty"`
+	Body   string  `json:"body,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+func (se sessionEntry) String() string {
+	if se.Params == "" {
+		return se.URI + se.Path
+	}
+	return se.URI + se.Path + "?" + se.Params
+}
+
+func (se sessionEntry) key() string {
+	return strings.Join([]string{se.URI, se.Path, se.Params}, "\n")
+}
+
+// NewRecordSession creates a session that will write every API response
+// to given file, any existing file content will be truncated.
+func NewRecordSession(path string) (*Session, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session file: %w", err)
+	}
+	return &Session{path: path, file: f}, nil
+}
+
+// NewReplaySession creates a session that will serve all API responses
+// from a file created using a record session.
+func NewReplaySession(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session file: %w", err)
+	}
+	defer f.Close()
+
+	s := Session{path: path, replay: true, entries: map[string][]sessionEntry{}}
+
+	var lineno int
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024*1024)
+	for scanner.Scan() {
+		lineno++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var se sessionEntry
+		if err = json.Unmarshal(scanner.Bytes(), &se); err != nil {
+			return nil, fmt.Errorf("failed to parse session file %s at line %d: %w", path, lineno, err)
+		}
+		s.entries[se.key()] = append(s.entries[se.key()], se)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session file %s: %w", path, err)
+	}
+
+	log.Debug().Str("path", path).Int("responses", len(s.entries)).Msg("Loaded Prometheus session")
+	return &s, nil
+}
+
+func (s *Session) IsReplay() bool {
+	return s.replay
+}
+
+func (s *Session) Close() error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+func (s *Session) client(uri string, upstream api.Client) api.Client {
+	return &sessionClient{session: s, uri: uri, upstream: upstream}
+}
+
+func (s *Session) record(se sessionEntry) error {
+	line, err := json.Marshal(se)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// get returns a recorded response for given request, if there's more than one
+// response for it then the one with the closest offset is used.
+func (s *Session) get(req sessionEntry) (se sessionEntry, ok bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, e := range s.entries[req.key()] {
+		if !ok || math.Abs(e.Offset-req.Offset) < math.Abs(se.Offset-req.Offset) {
+			se, ok = e, true
+		}
+	}
+	return se, ok
+}
+
+// sessionClient implements api.Client and either passes all requests
+// to the upstream client while recording responses, or replays responses
+// that were previously recorded.
+type sessionClient struct {
+	session  *Session
+	uri      string
+	upstream api.Client
+}
+
+func (sc *sessionClient) URL(ep string, args map[string]string) *url.URL {
+	return sc.upstream.URL(ep, args)
+}
+
+func (sc *sessionClient) Do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	params, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) > 0 {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range form {
+			params[k] = append(params[k], v...)
+		}
+	}
+
+	se := sessionEntry{
+		URI:    sc.uri,
+		Path:   req.URL.Path,
+		Params: sessionParams(params),
+		Time:   sessionTime(params),
+	}
+	se.Offset = sessionOffset(se.Time)
+
+	if sc.session.replay {
+		return sc.replay(se)
+	}
+
+	resp, respBody, err := sc.upstream.Do(ctx, req)
+	if err != nil {
+		se.Error = err.Error()
+	} else {
+		se.Code = resp.StatusCode
+		se.Body = string(respBody)
+	}
+	if rerr := sc.session.record(se); rerr != nil {
+		log.Error().Err(rerr).Str("path", sc.session.path).Msg("Failed to record Prometheus response")
+	}
+	return resp, respBody, err
+}
+
+func (sc *sessionClient) replay(req sessionEntry) (*http.Response, []byte, error) {
+	se, ok := sc.session.get(req)
+	if !ok {
+		return nil, nil, fmt.Errorf("no recorded response for %s in %s", req, sc.session.path)
+	}
+	if se.Error != "" {
+		return nil, nil, errors.New(se.Error)
+	}
+
+	body := []byte(se.Body)
+	if se.Code/100 == 2 && se.Time != "" && req.Time != "" {
+		recorded, err := parseFixtureTime(se.Time, time.Time{})
+		if err != nil {
+			return nil, nil, err
+		}
+		current, err := parseFixtureTime(req.Time, time.Time{})
+		if err != nil {
+			return nil, nil, err
+		}
+		body, err = shiftResponse(body, current.Sub(recorded))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode recorded response for %s: %w", req, err)
+		}
+	}
+
+	return &http.Response{
+		StatusCode: se.Code,
+		Status:     fmt.Sprintf("%d %s", se.Code, http.StatusText(se.Code)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, body, nil
+}
+
+// sessionParams returns request parameters that identify a recorded response.
+// Timestamps will be different on every run, so instead of them we only
+// use the length of the queried time range.
+func sessionParams(params url.Values) string {
+	key := url.Values{}
+	for k, v := range params {
+		switch k {
+		case "time", "start", "end", "timeout":
+		default:
+			key[k] = v
+		}
+	}
+	if start, end := params.Get("start"), params.Get("end"); start != "" && end != "" {
+		s, serr := parseFixtureTime(start, time.Time{})
+		e, eerr := parseFixtureTime(end, time.Time{})
+		if serr == nil && eerr == nil {
+			key.Set("range", model.Duration(e.Sub(s).Round(time.Second)).String())
+		}
+	}
+	return key.Encode()
+}
+
+// sessionTime returns the evaluation time of a query request.
+func sessionTime(params url.Values) string {
+	if t := params.Get("time"); t != "" {
+		return t
+	}
+	return params.Get("end")
+}
+
+// sessionOffset returns the number of seconds between given query time and now.
+func sessionOffset(t string) float64 {
+	if t == "" {
+		return 0
+	}
+	ts, err := parseFixtureTime(t, time.Time{})
+	if err != nil {
+		return 0
+	}
+	return math.Round(time.Since(ts).Seconds())
+}
+
+// shiftResponse moves all timestamps in a recorded query response by given
+// delta, so that replayed results match the time range of the current query.
+func shiftResponse(body []byte, delta time.Duration) ([]byte, error) {
+	var resp struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType string          `json:"errorType,omitempty"`
+		Error     string          `json:"error,omitempty"`
+		Warnings  []string        `json:"warnings,omitempty"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		ResultType model.ValueType `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil || data.Result == nil {
+		// not a query response
+		return body, nil
+	}
+
+	var result interface{}
+	switch data.ResultType {
+	case model.ValVector:
+		var vector model.Vector
+		if err := json.Unmarshal(data.Result, &vector); err != nil {
+			return nil, err
+		}
+		for _, s := range vector {
+			s.Timestamp = s.Timestamp.Add(delta)
+		}
+		result = vector
+	case model.ValMatrix:
+		var matrix model.Matrix
+		if err := json.Unmarshal(data.Result, &matrix); err != nil {
+			return nil, err
+		}
+		for _, ss := range matrix {
+			for i := range ss.Values {
+				ss.Values[i].Timestamp = ss.Values[i].Timestamp.Add(delta)
+			}
+		}
+		result = matrix
+	default:
+		return body, nil
+	}
+
+	var err error
+	data.Result, err = json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data, err = json.Marshal(data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(resp)
+}
@@ -0,0 +1,233 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestSession(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		err := r.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/status/config":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 30s\n"}}`))
+		case "/api/v1/query":
+			if r.Form.Get("query") == "error" {
+				w.WriteHeader(422)
+				_, _ = w.Write([]byte(`{"status":"error","errorType":"execution","error":"query failed"}`))
+				return
+			}
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[` + r.Form.Get("time") + `,"1"]}]}}`))
+		case "/api/v1/query_range":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"foo"},"values":[[` + r.Form.Get("start") + `,"1"],[` + r.Form.Get("end") + `,"1"]]}]}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	session, err := promapi.NewRecordSession(path)
+	require.NoError(t, err)
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetSession(session)
+
+	qr, err := prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+
+	_, err = prom.Query(context.Background(), "error")
+	require.EqualError(t, err, "execution: query failed")
+
+	rqr, err := prom.RangeQuery(context.Background(), "up", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, rqr.Samples, 1)
+
+	cfg, err := prom.Config(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, time.Second*30, cfg.Config.Global.ScrapeInterval)
+
+	require.NoError(t, session.Close())
+	require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 4)
+
+	time.Sleep(time.Second * 2)
+
+	session, err = promapi.NewReplaySession(path)
+	require.NoError(t, err)
+	require.True(t, session.IsReplay())
+	prom = promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetSession(session)
+
+	before := model.TimeFromUnixNano(time.Now().UnixNano()).Add(-time.Second)
+	qr, err = prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.LabelValue("foo"), qr.Series[0].Metric["job"])
+	require.True(t, qr.Series[0].Timestamp.After(before), "replayed timestamp should be shifted to current time")
+
+	_, err = prom.Query(context.Background(), "error")
+	require.EqualError(t, err, "execution: query failed")
+	require.False(t, promapi.IsUnavailableError(err))
+
+	rqr, err = prom.RangeQuery(context.Background(), "up", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, rqr.Samples, 1)
+	require.Len(t, rqr.Samples[0].Values, 2)
+	require.Equal(t, model.TimeFromUnixNano(rqr.End.UnixNano()).Unix(), rqr.Samples[0].Values[1].Timestamp.Unix())
+
+	cfg, err = prom.Config(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, time.Second*30, cfg.Config.Global.ScrapeInterval)
+
+	_, err = prom.Query(context.Background(), "foo")
+	require.ErrorContains(t, err, "no recorded response for "+srv.URL+"/api/v1/query?query=foo in "+path)
+	require.True(t, promapi.IsUnavailableError(err))
+
+	require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+}
+
+func TestSessionErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := promapi.NewReplaySession(filepath.Join(dir, "missing.jsonl"))
+	require.ErrorContains(t, err, "failed to open session file: ")
+
+	path := filepath.Join(dir, "broken.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"uri\":\"http://localhost\",\"path\":\"/api/v1/query\"}\n\n{\n"), 0o644))
+	_, err = promapi.NewReplaySession(path)
+	require.EqualError(t, err, "failed to parse session file "+path+" at line 3: unexpected end of JSON input")
+
+	_, err = promapi.NewRecordSession(filepath.Join(dir, "missing", "session.jsonl"))
+	require.ErrorContains(t, err, "failed to create session file: ")
+}
+
+func TestSessionRangeChunks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// return a single sample at the end of each chunk with the number
+		// of days between chunk end and now as the value
+		end, _ := strconv.ParseFloat(r.Form.Get("end"), 64)
+		days := int(time.Since(time.Unix(int64(end), 0)).Round(time.Hour).Hours() / 24)
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"foo"},"values":[[` + r.Form.Get("end") + `,"` + strconv.Itoa(days) + `"]]}]}}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	expected := []model.SampleValue{6, 5, 4, 3, 2, 1, 0}
+
+	session, err := promapi.NewRecordSession(path)
+	require.NoError(t, err)
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetSession(session)
+
+	rqr, err := prom.RangeQuery(context.Background(), "up", time.Hour*24*7, time.Minute*5)
+	require.NoError(t, err)
+	require.NoError(t, session.Close())
+	require.Len(t, rqr.Samples, 1)
+	values := []model.SampleValue{}
+	for _, v := range rqr.Samples[0].Values {
+		values = append(values, v.Value)
+	}
+	require.Equal(t, expected, values)
+
+	srv.Close()
+
+	session, err = promapi.NewReplaySession(path)
+	require.NoError(t, err)
+	prom = promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetSession(session)
+
+	rqr, err = prom.RangeQuery(context.Background(), "up", time.Hour*24*7, time.Minute*5)
+	require.NoError(t, err)
+	require.Len(t, rqr.Samples, 1)
+	values = []model.SampleValue{}
+	for _, v := range rqr.Samples[0].Values {
+		values = append(values, v.Value)
+	}
+	require.Equal(t, expected, values)
+}
+
+func TestSessionDiskCache(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[1614859502.068,"1"]}]}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "cache")
+	path := filepath.Join(dir, "session.jsonl")
+	ttls := map[string]time.Duration{"/api/v1/query": time.Hour}
+
+	record := func() {
+		session, err := promapi.NewRecordSession(path)
+		require.NoError(t, err)
+		prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+		prom.SetDiskCache(promapi.NewDiskCache(cacheDir, ttls))
+		prom.SetSession(session)
+
+		qr, err := prom.Query(context.Background(), "up")
+		require.NoError(t, err)
+		require.Len(t, qr.Series, 1)
+		require.NoError(t, session.Close())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 1)
+	}
+
+	record()
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	record()
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	srv.Close()
+
+	session, err := promapi.NewReplaySession(path)
+	require.NoError(t, err)
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, nil)
+	prom.SetDiskCache(promapi.NewDiskCache(cacheDir, ttls))
+	prom.SetSession(session)
+
+	qr, err := prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	require.NoDirExists(t, cacheDir)
+}
@@ -120,7 +120,7 @@ func (r BitBucketReporter) makeAnnotation(report Report, pb git.FileBlames) (ann
 	a := BitBucketAnnotation{
 		Path:     report.Path,
 		Line:     reportLine,
-		Message:  fmt.Sprintf("%s: %s", report.Problem.Reporter, report.Problem.Text),
+		Message:  fmt.Sprintf("%s: %s", report.Problem.Reporter, problemText(report)),
 		Severity: severity,
 		Type:     atype,
 		Link:     fmt.Sprintf("https://cloudflare.github.io/pint/checks/%s.html", report.Problem.Reporter),
@@ -359,6 +359,63 @@ func TestBitBucketReporter(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			description: "uses problem position when reporting line",
+			gitCmd: func(args ...string) ([]byte, error) {
+				if args[0] == "rev-parse" {
+					return []byte("fake-commit-id"), nil
+				}
+				if args[0] == "blame" {
+					content := blameLine("fake-commit-id", 2, "foo.txt", "expr: |") +
+						blameLine("fake-commit-id", 3, "foo.txt", "  foo{job=~\"bar\"}") +
+						blameLine("fake-commit-id", 4, "foo.txt", "  / bar")
+					return []byte(content), nil
+				}
+				return nil, nil
+			},
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2, 3, 4},
+						Rule:          mockRules[1],
+						Problem: checks.Problem{
+							Fragment: `foo{job=~"bar"}`,
+							Lines:    []int{2, 3, 4},
+							Reporter: "mock",
+							Text:     "unnecessary regexp",
+							Severity: checks.Bug,
+							Position: &parser.PositionRange{
+								Start: parser.Position{Line: 3, Column: 3},
+								End:   parser.Position{Line: 3, Column: 17},
+							},
+						},
+					},
+				},
+			},
+			report: reporter.BitBucketReport{
+				Title:  "Pint - Prometheus rules linter (version: v0.0.0)",
+				Result: "FAIL",
+			},
+			annotations: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						Path:     "foo.txt",
+						Line:     3,
+						Message:  "mock: unnecessary regexp",
+						Severity: "MEDIUM",
+						Type:     "BUG",
+						Link:     "https://cloudflare.github.io/pint/checks/mock.html",
+					},
+				},
+			},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %w", err)
+				}
+				return nil
+			},
+		},
 		{
 			description: "sends a correct empty report",
 			gitCmd: func(args ...string) ([]byte, error) {
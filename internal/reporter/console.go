@@ -70,14 +70,7 @@ func (cr ConsoleReporter) Submit(summary Summary) error {
 		msg := []string{}
 		firstLine, lastLine := report.Problem.LineRange()
 		msg = append(msg, color.CyanString("%s:%s: ", report.Path, printLineRange(firstLine, lastLine)))
-		switch report.Problem.Severity {
-		case checks.Bug, checks.Fatal:
-			msg = append(msg, color.RedString(report.Problem.Text))
-		case checks.Warning:
-			msg = append(msg, color.YellowString(report.Problem.Text))
-		default:
-			msg = append(msg, color.HiBlackString(report.Problem.Text))
-		}
+		msg = append(msg, severityColor(report.Problem.Severity)(problemText(report)))
 		msg = append(msg, color.MagentaString(" (%s)\n", report.Problem.Reporter))
 
 		lines := strings.Split(content, "\n")
@@ -85,8 +78,12 @@ func (cr ConsoleReporter) Submit(summary Summary) error {
 			lastLine = len(lines) - 1
 			log.Warn().Str("path", report.Path).Msgf("Tried to read more lines than present in the source file, this is likely due to '\n' usage in some rules, see https://github.com/cloudflare/pint/issues/20 for details")
 		}
-		for _, c := range lines[firstLine-1 : lastLine] {
+		pos := report.Problem.Position
+		for i, c := range lines[firstLine-1 : lastLine] {
 			msg = append(msg, color.WhiteString("%s\n", c))
+			if pos != nil && pos.Start.Line == pos.End.Line && pos.Start.Line == firstLine+i {
+				msg = append(msg, severityColor(report.Problem.Severity)(underline(c, pos.Start.Column, pos.End.Column)+"\n"))
+			}
 		}
 		perFile[report.Path] = append(perFile[report.Path], strings.Join(msg, ""))
 	}
@@ -122,6 +119,36 @@ func readFile(path string) (string, error) {
 	return string(content), nil
 }
 
+func severityColor(s checks.Severity) func(format string, a ...interface{}) string {
+	switch s {
+	case checks.Bug, checks.Fatal:
+		return color.RedString
+	case checks.Warning:
+		return color.YellowString
+	default:
+		return color.HiBlackString
+	}
+}
+
+// underline returns a marker line pointing at columns from start to end
+// (inclusive) of given source line, tabs are preserved so the marker is
+// aligned with the line printed above it.
+func underline(line string, start, end int) string {
+	var b strings.Builder
+	for i, r := range []rune(line) {
+		if i >= start-1 {
+			break
+		}
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	b.WriteString(strings.Repeat("^", end-start+1))
+	return b.String()
+}
+
 func printLineRange(s, e int) string {
 	if s == e {
 		return strconv.Itoa(s)
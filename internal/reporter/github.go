@@ -71,10 +71,19 @@ func (gr GithubReporter) Submit(summary Summary) error {
 
 		var comment *github.DraftReviewComment
 
-		if len(rep.ModifiedLines) == 1 {
+		if start, end, ok := positionLines(rep); ok {
 			comment = &github.DraftReviewComment{
 				Path: github.String(rep.Path),
-				Body: github.String(rep.Problem.Text),
+				Body: github.String(problemText(rep)),
+				Line: github.Int(end),
+			}
+			if start != end {
+				comment.StartLine = github.Int(start)
+			}
+		} else if len(rep.ModifiedLines) == 1 {
+			comment = &github.DraftReviewComment{
+				Path: github.String(rep.Path),
+				Body: github.String(problemText(rep)),
 				Line: github.Int(rep.ModifiedLines[0]),
 			}
 		} else if len(rep.ModifiedLines) > 1 {
@@ -82,7 +91,7 @@ func (gr GithubReporter) Submit(summary Summary) error {
 			start, end := rep.ModifiedLines[0], rep.ModifiedLines[len(rep.ModifiedLines)-1]
 			comment = &github.DraftReviewComment{
 				Path:      github.String(rep.Path),
-				Body:      github.String(rep.Problem.Text),
+				Body:      github.String(problemText(rep)),
 				Line:      github.Int(end),
 				StartLine: github.Int(start),
 			}
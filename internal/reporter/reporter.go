@@ -1,6 +1,8 @@
 package reporter
 
 import (
+	"fmt"
+
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/git"
 	"github.com/cloudflare/pint/internal/parser"
@@ -76,6 +78,10 @@ func shouldReport(report Report) bool {
 }
 
 func reportedLine(report Report) (l int) {
+	if start, _, ok := positionLines(report); ok {
+		return start
+	}
+
 	l = -1
 	for _, pl := range report.Problem.Lines {
 		for _, ml := range report.ModifiedLines {
@@ -93,3 +99,35 @@ func reportedLine(report Report) (l int) {
 
 	return
 }
+
+// positionLines returns the first and the last line of the exact problem
+// position, but only if all those lines were modified.
+func positionLines(report Report) (start, end int, ok bool) {
+	pos := report.Problem.Position
+	if pos == nil {
+		return 0, 0, false
+	}
+	for l := pos.Start.Line; l <= pos.End.Line; l++ {
+		var isModified bool
+		for _, ml := range report.ModifiedLines {
+			if ml == l {
+				isModified = true
+				break
+			}
+		}
+		if !isModified {
+			return 0, 0, false
+		}
+	}
+	return pos.Start.Line, pos.End.Line, true
+}
+
+// problemText returns the problem description, problems reported for
+// queries from Grafana dashboards are prefixed with the JSON path of
+// the query so it's easy to find which panel it belongs to.
+func problemText(report Report) string {
+	if report.Rule.DashboardQuery != nil {
+		return fmt.Sprintf("%s: %s", report.Rule.DashboardQuery.Path, report.Problem.Text)
+	}
+	return report.Problem.Text
+}
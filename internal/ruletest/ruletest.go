@@ -0,0 +1,207 @@
+package ruletest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	defaultEvaluationInterval = model.Duration(time.Minute)
+
+	ruleFilesKey      = "rule_files"
+	testsKey          = "tests"
+	alertRuleTestKey  = "alert_rule_test"
+	promqlExprTestKey = "promql_expr_test"
+)
+
+// File is a rule unit test file using the same format as promtool.
+// See https://prometheus.io/docs/prometheus/latest/configuration/unit_testing_rules/
+type File struct {
+	Path               string         `yaml:"-"`
+	RuleFiles          []string       `yaml:"rule_files"`
+	EvaluationInterval model.Duration `yaml:"evaluation_interval,omitempty"`
+	GroupEvalOrder     []string       `yaml:"group_eval_order"`
+	Tests              []TestGroup    `yaml:"tests"`
+
+	// line numbers of keys in the test file, used when reporting failures
+	ruleFilesLine int
+}
+
+// TestGroup is a group of input series and tests run against them.
+type TestGroup struct {
+	Interval        model.Duration   `yaml:"interval"`
+	InputSeries     []Series         `yaml:"input_series"`
+	AlertRuleTests  []AlertTestCase  `yaml:"alert_rule_test,omitempty"`
+	PromQLExprTests []PromQLTestCase `yaml:"promql_expr_test,omitempty"`
+	ExternalLabels  labels.Labels    `yaml:"external_labels,omitempty"`
+	ExternalURL     string           `yaml:"external_url,omitempty"`
+	Name            string           `yaml:"name,omitempty"`
+
+	line int
+}
+
+type Series struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+type AlertTestCase struct {
+	EvalTime  model.Duration `yaml:"eval_time"`
+	Alertname string         `yaml:"alertname"`
+	ExpAlerts []Alert        `yaml:"exp_alerts"`
+
+	line int
+}
+
+type Alert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+type PromQLTestCase struct {
+	Expr       string         `yaml:"expr"`
+	EvalTime   model.Duration `yaml:"eval_time"`
+	ExpSamples []Sample       `yaml:"exp_samples"`
+
+	line int
+}
+
+type Sample struct {
+	Labels string  `yaml:"labels"`
+	Value  float64 `yaml:"value"`
+}
+
+// Failure is a single problem found when running tests from a file.
+type Failure struct {
+	Line int
+	Text string
+}
+
+// Load reads and parses a test file, all rule file paths are resolved
+// relative to the directory of the test file and glob patterns are expanded.
+func Load(path string) (f File, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err = dec.Decode(&f); err != nil {
+		return f, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	f.Path = path
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		return f, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	f.setLines(&doc)
+
+	if f.EvaluationInterval == 0 {
+		f.EvaluationInterval = defaultEvaluationInterval
+	}
+	for i := range f.Tests {
+		if f.Tests[i].Interval == 0 {
+			f.Tests[i].Interval = f.EvaluationInterval
+		}
+	}
+
+	var ruleFiles []string
+	for _, rf := range f.RuleFiles {
+		if rf != "" && !filepath.IsAbs(rf) {
+			rf = filepath.Join(filepath.Dir(path), rf)
+		}
+		matches, err := filepath.Glob(rf)
+		if err != nil {
+			return f, err
+		}
+		ruleFiles = append(ruleFiles, matches...)
+	}
+	f.RuleFiles = ruleFiles
+
+	return f, nil
+}
+
+// Covers returns true if this file has tests for given alert defined
+// in a rule file at given path.
+func (f File) Covers(path, alertname string) bool {
+	var hasFile bool
+	for _, rf := range f.RuleFiles {
+		if samePath(rf, path) {
+			hasFile = true
+			break
+		}
+	}
+	if !hasFile {
+		return false
+	}
+
+	for _, tg := range f.Tests {
+		for _, at := range tg.AlertRuleTests {
+			if at.Alertname == alertname {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *File) setLines(doc *yaml.Node) {
+	f.ruleFilesLine = 1
+	if len(doc.Content) == 0 {
+		return
+	}
+	root := doc.Content[0]
+
+	if key, _ := parser.MappingKeyValue(root, ruleFilesKey); key != nil {
+		f.ruleFilesLine = key.Line
+	}
+
+	_, tests := parser.MappingKeyValue(root, testsKey)
+	for i, tg := range sequenceItems(tests) {
+		if i >= len(f.Tests) {
+			break
+		}
+		f.Tests[i].line = tg.Line
+
+		_, alertTests := parser.MappingKeyValue(tg, alertRuleTestKey)
+		for j, at := range sequenceItems(alertTests) {
+			if j < len(f.Tests[i].AlertRuleTests) {
+				f.Tests[i].AlertRuleTests[j].line = at.Line
+			}
+		}
+
+		_, exprTests := parser.MappingKeyValue(tg, promqlExprTestKey)
+		for j, et := range sequenceItems(exprTests) {
+			if j < len(f.Tests[i].PromQLExprTests) {
+				f.Tests[i].PromQLExprTests[j].line = et.Line
+			}
+		}
+	}
+}
+
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	aa, errA := filepath.Abs(a)
+	ab, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && aa == ab
+}
+
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
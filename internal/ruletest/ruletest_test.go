@@ -0,0 +1,217 @@
+package ruletest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/ruletest"
+)
+
+const testRules = `groups:
+- name: example
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by(job)
+  - alert: InstanceDown
+    expr: up == 0
+    for: 5m
+    labels:
+      severity: page
+    annotations:
+      summary: '{{ $labels.instance }} is down'
+`
+
+func TestRun(t *testing.T) {
+	type testCaseT struct {
+		description string
+		test        string
+		failures    []ruletest.Failure
+		err         string
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "passing tests",
+			test: `rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  input_series:
+  - series: up{job="node", instance="a"}
+    values: 1 1 0 0 0 0 0 0 0 0
+  - series: up{job="node", instance="b"}
+    values: 1x10
+  alert_rule_test:
+  - eval_time: 2m
+    alertname: InstanceDown
+  - eval_time: 8m
+    alertname: InstanceDown
+    exp_alerts:
+    - exp_labels:
+        severity: page
+        job: node
+        instance: a
+      exp_annotations:
+        summary: a is down
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 5m
+    exp_samples:
+    - labels: job:up:sum{job="node"}
+      value: 1
+`,
+		},
+		{
+			description: "failing tests",
+			test: `rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  name: down
+  input_series:
+  - series: up{job="node", instance="a"}
+    values: 0x10
+  alert_rule_test:
+  - eval_time: 2m
+    alertname: InstanceDown
+    exp_alerts:
+    - exp_labels:
+        severity: page
+        job: node
+        instance: a
+  - eval_time: 10m
+    alertname: InstanceDown
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 5m
+    exp_samples:
+    - labels: job:up:sum{job="node"}
+      value: 1
+`,
+			failures: []ruletest.Failure{
+				{
+					Line: 10,
+					Text: `"InstanceDown" alert at 2m in "down" test group doesn't match expected alerts, expected: {alertname="InstanceDown", instance="a", job="node", severity="page"}, got: no alerts`,
+				},
+				{
+					Line: 17,
+					Text: `"InstanceDown" alert at 10m in "down" test group doesn't match expected alerts, expected: no alerts, got: {alertname="InstanceDown", instance="a", job="node", severity="page"} with annotations {summary="a is down"}`,
+				},
+				{
+					Line: 20,
+					Text: `"job:up:sum" query at 5m in "down" test group returned unexpected results, expected: job:up:sum{job="node"} 1, got: job:up:sum{job="node"} 0`,
+				},
+			},
+		},
+		{
+			description: "query error",
+			test: `rule_files:
+- rules.yml
+evaluation_interval: 30s
+tests:
+- input_series:
+  - series: up{job="node", instance="a"}
+    values: 1
+  promql_expr_test:
+  - expr: sum(foo{)
+    eval_time: 1m
+`,
+			failures: []ruletest.Failure{
+				{
+					Line: 9,
+					Text: `failed to run "sum(foo{)" query at 1m: 1:9: parse error: unexpected character inside braces: ')'`,
+				},
+			},
+		},
+		{
+			description: "missing rule files",
+			test: `rule_files:
+- missing.yml
+tests: []
+`,
+			failures: []ruletest.Failure{
+				{Line: 1, Text: "no rule files found"},
+			},
+		},
+		{
+			description: "missing alertname",
+			test: `rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  alert_rule_test:
+  - eval_time: 1m
+`,
+			failures: []ruletest.Failure{
+				{Line: 6, Text: "alertname is required for all alert_rule_test items"},
+			},
+		},
+		{
+			description: "invalid input series",
+			test: `rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  input_series:
+  - series: up{job=
+    values: 1
+`,
+			failures: []ruletest.Failure{
+				{Line: 4, Text: `failed to load input_series: 1:9: parse error: unexpected character inside braces: '1'`},
+			},
+		},
+		{
+			description: "unknown key",
+			test: `rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  alerts: []
+`,
+			err: "failed to parse test.yml: yaml: unmarshal errors:\n  line 5: field alerts not found in type ruletest.TestGroup",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yml"), []byte(testRules), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "test.yml"), []byte(tc.test), 0o644))
+
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(dir))
+			defer func() { _ = os.Chdir(wd) }()
+
+			f, err := ruletest.Load("test.yml")
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.failures, f.Run())
+		})
+	}
+}
+
+func TestCovers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yml"), []byte(testRules), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.yml"), []byte(`rule_files:
+- rules.yml
+tests:
+- alert_rule_test:
+  - eval_time: 1m
+    alertname: InstanceDown
+`), 0o644))
+
+	f, err := ruletest.Load(filepath.Join(dir, "test.yml"))
+	require.NoError(t, err)
+
+	require.True(t, f.Covers(filepath.Join(dir, "rules.yml"), "InstanceDown"))
+	require.False(t, f.Covers(filepath.Join(dir, "rules.yml"), "InstanceUp"))
+	require.False(t, f.Covers(filepath.Join(dir, "other.yml"), "InstanceDown"))
+}
@@ -0,0 +1,385 @@
+package ruletest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// Run evaluates all rules loaded from rule files listed in the test file
+// and compares results with expected alerts and query results.
+func (f File) Run() (failures []Failure) {
+	if len(f.RuleFiles) == 0 {
+		return []Failure{{Line: f.ruleFilesLine, Text: "no rule files found"}}
+	}
+
+	groupOrder := map[string]int{}
+	for i, name := range f.GroupEvalOrder {
+		if _, ok := groupOrder[name]; ok {
+			return []Failure{{Line: f.ruleFilesLine, Text: fmt.Sprintf("group name repeated in evaluation order: %s", name)}}
+		}
+		groupOrder[name] = i
+	}
+
+	for _, tg := range f.Tests {
+		failures = append(failures, tg.run(f.ruleFilesLine, time.Duration(f.EvaluationInterval), groupOrder, f.RuleFiles)...)
+	}
+	return failures
+}
+
+func (tg TestGroup) run(ruleFilesLine int, evalInterval time.Duration, groupOrder map[string]int, ruleFiles []string) (failures []Failure) {
+	for _, at := range tg.AlertRuleTests {
+		if at.Alertname == "" {
+			return []Failure{{Line: at.line, Text: fmt.Sprintf("alertname is required for all alert_rule_test items%s", tg.nameText())}}
+		}
+	}
+
+	suite, err := promql.NewLazyLoader(nil, tg.seriesLoadingString(), promql.LazyLoaderOpts{
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+	if err != nil {
+		suite.Close()
+		return []Failure{{Line: tg.line, Text: fmt.Sprintf("failed to load input_series%s: %s", tg.nameText(), err)}}
+	}
+	defer suite.Close()
+	suite.SubqueryInterval = evalInterval
+
+	m := rules.NewManager(&rules.ManagerOptions{
+		QueryFunc:  rules.EngineQueryFunc(suite.QueryEngine(), suite.Storage()),
+		Appendable: suite.Storage(),
+		Context:    context.Background(),
+		NotifyFunc: func(ctx context.Context, expr string, alerts ...*rules.Alert) {},
+		Logger:     log.NewNopLogger(),
+	})
+	groupsMap, errs := m.LoadGroups(time.Duration(tg.Interval), tg.ExternalLabels, tg.ExternalURL, nil, ruleFiles...)
+	if errs != nil {
+		for _, err := range errs {
+			failures = append(failures, Failure{Line: ruleFilesLine, Text: fmt.Sprintf("failed to load rules: %s", err)})
+		}
+		return failures
+	}
+	groups := orderedGroups(groupsMap, groupOrder)
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			if ar, ok := r.(*rules.AlertingRule); ok {
+				// mark alerting rules as restored so ALERTS series are created
+				ar.SetRestored(true)
+			}
+		}
+	}
+
+	alertTests := map[model.Duration][]AlertTestCase{}
+	evalTimes := []model.Duration{}
+	for _, at := range tg.AlertRuleTests {
+		if _, ok := alertTests[at.EvalTime]; !ok {
+			evalTimes = append(evalTimes, at.EvalTime)
+		}
+		alertTests[at.EvalTime] = append(alertTests[at.EvalTime], at)
+	}
+	sort.Slice(evalTimes, func(i, j int) bool {
+		return evalTimes[i] < evalTimes[j]
+	})
+
+	mint := time.Unix(0, 0).UTC()
+	maxt := mint.Add(tg.maxEvalTime())
+	var curr int
+	for ts := mint; !ts.After(maxt); ts = ts.Add(evalInterval) {
+		var evalFailures []Failure
+		suite.WithSamplesTill(ts, func(err error) {
+			if err != nil {
+				evalFailures = append(evalFailures, Failure{Line: tg.line, Text: fmt.Sprintf("failed to load input_series%s: %s", tg.nameText(), err)})
+				return
+			}
+			for _, g := range groups {
+				g.Eval(suite.Context(), ts)
+				for _, r := range g.Rules() {
+					if r.LastError() != nil {
+						evalFailures = append(evalFailures, Failure{
+							Line: tg.line,
+							Text: fmt.Sprintf("failed to evaluate %q rule at %s%s: %s", r.Name(), model.Duration(ts.Sub(mint)), tg.nameText(), r.LastError()),
+						})
+					}
+				}
+			}
+		})
+		if len(evalFailures) > 0 {
+			return append(failures, evalFailures...)
+		}
+
+		// alerts are compared with results of the last evaluation that
+		// happened at or before eval_time
+		for curr < len(evalTimes) && time.Duration(evalTimes[curr]) < ts.Add(evalInterval).Sub(mint) {
+			for _, at := range alertTests[evalTimes[curr]] {
+				if failure, ok := at.compare(groups, tg.nameText()); !ok {
+					failures = append(failures, failure)
+				}
+			}
+			curr++
+		}
+	}
+
+	for _, et := range tg.PromQLExprTests {
+		if failure, ok := et.compare(suite, mint, tg.nameText()); !ok {
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures
+}
+
+func (tg TestGroup) nameText() string {
+	if tg.Name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in %q test group", tg.Name)
+}
+
+// seriesLoadingString returns input series in the format used by PromQL tests.
+func (tg TestGroup) seriesLoadingString() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "load %s\n", tg.Interval)
+	for _, is := range tg.InputSeries {
+		fmt.Fprintf(&b, "  %s %s\n", is.Series, is.Values)
+	}
+	return b.String()
+}
+
+func (tg TestGroup) maxEvalTime() time.Duration {
+	var maxd model.Duration
+	for _, at := range tg.AlertRuleTests {
+		if at.EvalTime > maxd {
+			maxd = at.EvalTime
+		}
+	}
+	for _, et := range tg.PromQLExprTests {
+		if et.EvalTime > maxd {
+			maxd = et.EvalTime
+		}
+	}
+	return time.Duration(maxd)
+}
+
+func (at AlertTestCase) compare(groups []*rules.Group, nameText string) (Failure, bool) {
+	var got labelsAndAnnotations
+	// the same alert can be defined in multiple groups
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			ar, ok := r.(*rules.AlertingRule)
+			if !ok || ar.Name() != at.Alertname {
+				continue
+			}
+			for _, a := range ar.ActiveAlerts() {
+				if a.State == rules.StateFiring {
+					got = append(got, labelAndAnnotation{
+						Labels:      a.Labels.Copy(),
+						Annotations: a.Annotations.Copy(),
+					})
+				}
+			}
+		}
+	}
+
+	var exp labelsAndAnnotations
+	for _, a := range at.ExpAlerts {
+		lset := map[string]string{}
+		for k, v := range a.ExpLabels {
+			lset[k] = v
+		}
+		// alertname label is added by Prometheus, so it's not part of exp_labels
+		lset[labels.AlertName] = at.Alertname
+		exp = append(exp, labelAndAnnotation{
+			Labels:      labels.FromMap(lset),
+			Annotations: labels.FromMap(a.ExpAnnotations),
+		})
+	}
+
+	sort.Sort(got)
+	sort.Sort(exp)
+	if exp.equal(got) {
+		return Failure{}, true
+	}
+	return Failure{
+		Line: at.line,
+		Text: fmt.Sprintf("%q alert at %s%s doesn't match expected alerts, expected: %s, got: %s", at.Alertname, at.EvalTime, nameText, exp, got),
+	}, false
+}
+
+func (et PromQLTestCase) compare(suite *promql.LazyLoader, mint time.Time, nameText string) (Failure, bool) {
+	result, err := query(suite.Context(), et.Expr, mint.Add(time.Duration(et.EvalTime)), suite.QueryEngine(), suite.Queryable())
+	if err != nil {
+		return Failure{
+			Line: et.line,
+			Text: fmt.Sprintf("failed to run %q query at %s%s: %s", et.Expr, et.EvalTime, nameText, err),
+		}, false
+	}
+
+	got := make(samples, 0, len(result))
+	for _, s := range result {
+		got = append(got, sample{labels: s.Metric.Copy(), value: s.V})
+	}
+
+	exp := make(samples, 0, len(et.ExpSamples))
+	for _, s := range et.ExpSamples {
+		lset, err := promParser.ParseMetric(s.Labels)
+		if err != nil {
+			return Failure{
+				Line: et.line,
+				Text: fmt.Sprintf("failed to parse %q labels of expected %q query results%s: %s", s.Labels, et.Expr, nameText, err),
+			}, false
+		}
+		exp = append(exp, sample{labels: lset, value: s.Value})
+	}
+
+	sort.Sort(got)
+	sort.Sort(exp)
+	if exp.equal(got) {
+		return Failure{}, true
+	}
+	return Failure{
+		Line: et.line,
+		Text: fmt.Sprintf("%q query at %s%s returned unexpected results, expected: %s, got: %s", et.Expr, et.EvalTime, nameText, exp, got),
+	}, false
+}
+
+// orderedGroups returns all groups sorted using the order from group_eval_order,
+// groups not listed there are evaluated first.
+func orderedGroups(groupsMap map[string]*rules.Group, groupOrder map[string]int) []*rules.Group {
+	groups := make([]*rules.Group, 0, len(groupsMap))
+	for _, g := range groupsMap {
+		groups = append(groups, g)
+	}
+	order := func(g *rules.Group) int {
+		if i, ok := groupOrder[g.Name()]; ok {
+			return i
+		}
+		return -1
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if order(groups[i]) != order(groups[j]) {
+			return order(groups[i]) < order(groups[j])
+		}
+		return groups[i].Name() < groups[j].Name()
+	})
+	return groups
+}
+
+func query(ctx context.Context, qs string, ts time.Time, engine *promql.Engine, qu storage.Queryable) (promql.Vector, error) {
+	q, err := engine.NewInstantQuery(qu, nil, qs, ts)
+	if err != nil {
+		return nil, err
+	}
+	res := q.Exec(ctx)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	switch v := res.Value.(type) {
+	case promql.Vector:
+		return v, nil
+	case promql.Scalar:
+		return promql.Vector{promql.Sample{
+			Point:  promql.Point(v),
+			Metric: labels.Labels{},
+		}}, nil
+	default:
+		return nil, errors.New("rule result is not a vector or scalar")
+	}
+}
+
+type labelAndAnnotation struct {
+	Labels      labels.Labels
+	Annotations labels.Labels
+}
+
+func (la labelAndAnnotation) String() string {
+	if len(la.Annotations) == 0 {
+		return la.Labels.String()
+	}
+	return fmt.Sprintf("%s with annotations %s", la.Labels, la.Annotations)
+}
+
+type labelsAndAnnotations []labelAndAnnotation
+
+func (la labelsAndAnnotations) Len() int      { return len(la) }
+func (la labelsAndAnnotations) Swap(i, j int) { la[i], la[j] = la[j], la[i] }
+func (la labelsAndAnnotations) Less(i, j int) bool {
+	if diff := labels.Compare(la[i].Labels, la[j].Labels); diff != 0 {
+		return diff < 0
+	}
+	return labels.Compare(la[i].Annotations, la[j].Annotations) < 0
+}
+
+func (la labelsAndAnnotations) equal(other labelsAndAnnotations) bool {
+	if len(la) != len(other) {
+		return false
+	}
+	for i := range la {
+		if !labels.Equal(la[i].Labels, other[i].Labels) || !labels.Equal(la[i].Annotations, other[i].Annotations) {
+			return false
+		}
+	}
+	return true
+}
+
+func (la labelsAndAnnotations) String() string {
+	if len(la) == 0 {
+		return "no alerts"
+	}
+	s := make([]string, 0, len(la))
+	for _, l := range la {
+		s = append(s, l.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+type sample struct {
+	labels labels.Labels
+	value  float64
+}
+
+func (s sample) String() string {
+	return s.labels.Get(labels.MetricName) + s.labels.WithoutLabels(labels.MetricName).String() + " " + strconv.FormatFloat(s.value, 'f', -1, 64)
+}
+
+type samples []sample
+
+func (s samples) Len() int      { return len(s) }
+func (s samples) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s samples) Less(i, j int) bool {
+	return labels.Compare(s[i].labels, s[j].labels) < 0
+}
+
+func (s samples) equal(other samples) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if !labels.Equal(s[i].labels, other[i].labels) || s[i].value != other[i].value {
+			return false
+		}
+	}
+	return true
+}
+
+func (s samples) String() string {
+	if len(s) == 0 {
+		return "no results"
+	}
+	l := make([]string, 0, len(s))
+	for _, smpl := range s {
+		l = append(l, smpl.String())
+	}
+	return strings.Join(l, ", ")
+}